@@ -0,0 +1,1265 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.2
+// source: scopeservice/v1/scopeservice.proto
+
+package scopeservice
+
+import (
+	v12 "github.com/nlnwa/veidemann-api/go/commons/v1"
+	v1 "github.com/nlnwa/veidemann-api/go/frontier/v1"
+	v11 "github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Diagnostic_Severity int32
+
+const (
+	Diagnostic_ERROR   Diagnostic_Severity = 0
+	Diagnostic_WARNING Diagnostic_Severity = 1
+)
+
+// Enum value maps for Diagnostic_Severity.
+var (
+	Diagnostic_Severity_name = map[int32]string{
+		0: "ERROR",
+		1: "WARNING",
+	}
+	Diagnostic_Severity_value = map[string]int32{
+		"ERROR":   0,
+		"WARNING": 1,
+	}
+)
+
+func (x Diagnostic_Severity) Enum() *Diagnostic_Severity {
+	p := new(Diagnostic_Severity)
+	*p = x
+	return p
+}
+
+func (x Diagnostic_Severity) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Diagnostic_Severity) Descriptor() protoreflect.EnumDescriptor {
+	return file_scopeservice_v1_scopeservice_proto_enumTypes[0].Descriptor()
+}
+
+func (Diagnostic_Severity) Type() protoreflect.EnumType {
+	return &file_scopeservice_v1_scopeservice_proto_enumTypes[0]
+}
+
+func (x Diagnostic_Severity) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Diagnostic_Severity.Descriptor instead.
+func (Diagnostic_Severity) EnumDescriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{9, 0}
+}
+
+type ScopeCheckBatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	QueuedUri       []*v1.QueuedUri `protobuf:"bytes,1,rep,name=queued_uri,json=queuedUri,proto3" json:"queued_uri,omitempty"`
+	ScopeScriptName string          `protobuf:"bytes,2,opt,name=scope_script_name,json=scopeScriptName,proto3" json:"scope_script_name,omitempty"`
+	ScopeScript     string          `protobuf:"bytes,3,opt,name=scope_script,json=scopeScript,proto3" json:"scope_script,omitempty"`
+	Debug           bool            `protobuf:"varint,4,opt,name=debug,proto3" json:"debug,omitempty"`
+}
+
+func (x *ScopeCheckBatchRequest) Reset() {
+	*x = ScopeCheckBatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScopeCheckBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScopeCheckBatchRequest) ProtoMessage() {}
+
+func (x *ScopeCheckBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScopeCheckBatchRequest.ProtoReflect.Descriptor instead.
+func (*ScopeCheckBatchRequest) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ScopeCheckBatchRequest) GetQueuedUri() []*v1.QueuedUri {
+	if x != nil {
+		return x.QueuedUri
+	}
+	return nil
+}
+
+func (x *ScopeCheckBatchRequest) GetScopeScriptName() string {
+	if x != nil {
+		return x.ScopeScriptName
+	}
+	return ""
+}
+
+func (x *ScopeCheckBatchRequest) GetScopeScript() string {
+	if x != nil {
+		return x.ScopeScript
+	}
+	return ""
+}
+
+func (x *ScopeCheckBatchRequest) GetDebug() bool {
+	if x != nil {
+		return x.Debug
+	}
+	return false
+}
+
+type ScopeCheckBatchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response []*v11.ScopeCheckResponse `protobuf:"bytes,1,rep,name=response,proto3" json:"response,omitempty"`
+}
+
+func (x *ScopeCheckBatchResponse) Reset() {
+	*x = ScopeCheckBatchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScopeCheckBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScopeCheckBatchResponse) ProtoMessage() {}
+
+func (x *ScopeCheckBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScopeCheckBatchResponse.ProtoReflect.Descriptor instead.
+func (*ScopeCheckBatchResponse) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ScopeCheckBatchResponse) GetResponse() []*v11.ScopeCheckResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+type RegisterModuleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source string `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+}
+
+func (x *RegisterModuleRequest) Reset() {
+	*x = RegisterModuleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterModuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterModuleRequest) ProtoMessage() {}
+
+func (x *RegisterModuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterModuleRequest.ProtoReflect.Descriptor instead.
+func (*RegisterModuleRequest) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisterModuleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterModuleRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type RegisterModuleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *RegisterModuleResponse) Reset() {
+	*x = RegisterModuleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RegisterModuleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterModuleResponse) ProtoMessage() {}
+
+func (x *RegisterModuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterModuleResponse.ProtoReflect.Descriptor instead.
+func (*RegisterModuleResponse) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{3}
+}
+
+type ExplainResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response *v11.ScopeCheckResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
+	Trace    []*TraceEntry           `protobuf:"bytes,2,rep,name=trace,proto3" json:"trace,omitempty"`
+	Decision int32                   `protobuf:"varint,3,opt,name=decision,proto3" json:"decision,omitempty"`
+}
+
+func (x *ExplainResponse) Reset() {
+	*x = ExplainResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ExplainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExplainResponse) ProtoMessage() {}
+
+func (x *ExplainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExplainResponse.ProtoReflect.Descriptor instead.
+func (*ExplainResponse) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ExplainResponse) GetResponse() *v11.ScopeCheckResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *ExplainResponse) GetTrace() []*TraceEntry {
+	if x != nil {
+		return x.Trace
+	}
+	return nil
+}
+
+func (x *ExplainResponse) GetDecision() int32 {
+	if x != nil {
+		return x.Decision
+	}
+	return 0
+}
+
+type TraceEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Function string        `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	File     string        `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Line     int32         `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	Col      int32         `protobuf:"varint,4,opt,name=col,proto3" json:"col,omitempty"`
+	Args     []string      `protobuf:"bytes,5,rep,name=args,proto3" json:"args,omitempty"`
+	Kwargs   []*TraceValue `protobuf:"bytes,6,rep,name=kwargs,proto3" json:"kwargs,omitempty"`
+	Values   []*TraceValue `protobuf:"bytes,7,rep,name=values,proto3" json:"values,omitempty"`
+	Match    *bool         `protobuf:"varint,8,opt,name=match,proto3,oneof" json:"match,omitempty"`
+	Status   string        `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	Message  string        `protobuf:"bytes,10,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *TraceEntry) Reset() {
+	*x = TraceEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TraceEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceEntry) ProtoMessage() {}
+
+func (x *TraceEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceEntry.ProtoReflect.Descriptor instead.
+func (*TraceEntry) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TraceEntry) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *TraceEntry) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *TraceEntry) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *TraceEntry) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *TraceEntry) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *TraceEntry) GetKwargs() []*TraceValue {
+	if x != nil {
+		return x.Kwargs
+	}
+	return nil
+}
+
+func (x *TraceEntry) GetValues() []*TraceValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *TraceEntry) GetMatch() bool {
+	if x != nil && x.Match != nil {
+		return *x.Match
+	}
+	return false
+}
+
+func (x *TraceEntry) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TraceEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TraceValue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *TraceValue) Reset() {
+	*x = TraceValue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TraceValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TraceValue) ProtoMessage() {}
+
+func (x *TraceValue) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TraceValue.ProtoReflect.Descriptor instead.
+func (*TraceValue) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TraceValue) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TraceValue) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ValidateScriptRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ScopeScriptName string   `protobuf:"bytes,1,opt,name=scope_script_name,json=scopeScriptName,proto3" json:"scope_script_name,omitempty"`
+	ScopeScript     string   `protobuf:"bytes,2,opt,name=scope_script,json=scopeScript,proto3" json:"scope_script,omitempty"`
+	AnnotationKey   []string `protobuf:"bytes,3,rep,name=annotation_key,json=annotationKey,proto3" json:"annotation_key,omitempty"`
+}
+
+func (x *ValidateScriptRequest) Reset() {
+	*x = ValidateScriptRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateScriptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateScriptRequest) ProtoMessage() {}
+
+func (x *ValidateScriptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateScriptRequest.ProtoReflect.Descriptor instead.
+func (*ValidateScriptRequest) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidateScriptRequest) GetScopeScriptName() string {
+	if x != nil {
+		return x.ScopeScriptName
+	}
+	return ""
+}
+
+func (x *ValidateScriptRequest) GetScopeScript() string {
+	if x != nil {
+		return x.ScopeScript
+	}
+	return ""
+}
+
+func (x *ValidateScriptRequest) GetAnnotationKey() []string {
+	if x != nil {
+		return x.AnnotationKey
+	}
+	return nil
+}
+
+type ValidateScriptResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid      bool          `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Diagnostic []*Diagnostic `protobuf:"bytes,2,rep,name=diagnostic,proto3" json:"diagnostic,omitempty"`
+}
+
+func (x *ValidateScriptResponse) Reset() {
+	*x = ValidateScriptResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValidateScriptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateScriptResponse) ProtoMessage() {}
+
+func (x *ValidateScriptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateScriptResponse.ProtoReflect.Descriptor instead.
+func (*ValidateScriptResponse) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ValidateScriptResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateScriptResponse) GetDiagnostic() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostic
+	}
+	return nil
+}
+
+type Diagnostic struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Severity Diagnostic_Severity `protobuf:"varint,1,opt,name=severity,proto3,enum=veidemann.scopeservice.v1.Diagnostic_Severity" json:"severity,omitempty"`
+	File     string              `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Line     int32               `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	Col      int32               `protobuf:"varint,4,opt,name=col,proto3" json:"col,omitempty"`
+	Message  string              `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (x *Diagnostic) Reset() {
+	*x = Diagnostic{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Diagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic) ProtoMessage() {}
+
+func (x *Diagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic.ProtoReflect.Descriptor instead.
+func (*Diagnostic) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Diagnostic) GetSeverity() Diagnostic_Severity {
+	if x != nil {
+		return x.Severity
+	}
+	return Diagnostic_ERROR
+}
+
+func (x *Diagnostic) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetCol() int32 {
+	if x != nil {
+		return x.Col
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CanonicalizeStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uri string `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+}
+
+func (x *CanonicalizeStreamRequest) Reset() {
+	*x = CanonicalizeStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CanonicalizeStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CanonicalizeStreamRequest) ProtoMessage() {}
+
+func (x *CanonicalizeStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CanonicalizeStreamRequest.ProtoReflect.Descriptor instead.
+func (*CanonicalizeStreamRequest) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CanonicalizeStreamRequest) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+type CanonicalizeStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uri          string         `protobuf:"bytes,1,opt,name=uri,proto3" json:"uri,omitempty"`
+	CanonicalUri *v12.ParsedUri `protobuf:"bytes,2,opt,name=canonical_uri,json=canonicalUri,proto3" json:"canonical_uri,omitempty"`
+	Surt         string         `protobuf:"bytes,3,opt,name=surt,proto3" json:"surt,omitempty"`
+	HostAscii    string         `protobuf:"bytes,4,opt,name=host_ascii,json=hostAscii,proto3" json:"host_ascii,omitempty"`
+	HostUnicode  string         `protobuf:"bytes,5,opt,name=host_unicode,json=hostUnicode,proto3" json:"host_unicode,omitempty"`
+	Hash         string         `protobuf:"bytes,6,opt,name=hash,proto3" json:"hash,omitempty"`
+	Error        string         `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *CanonicalizeStreamResponse) Reset() {
+	*x = CanonicalizeStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CanonicalizeStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CanonicalizeStreamResponse) ProtoMessage() {}
+
+func (x *CanonicalizeStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_scopeservice_v1_scopeservice_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CanonicalizeStreamResponse.ProtoReflect.Descriptor instead.
+func (*CanonicalizeStreamResponse) Descriptor() ([]byte, []int) {
+	return file_scopeservice_v1_scopeservice_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CanonicalizeStreamResponse) GetUri() string {
+	if x != nil {
+		return x.Uri
+	}
+	return ""
+}
+
+func (x *CanonicalizeStreamResponse) GetCanonicalUri() *v12.ParsedUri {
+	if x != nil {
+		return x.CanonicalUri
+	}
+	return nil
+}
+
+func (x *CanonicalizeStreamResponse) GetSurt() string {
+	if x != nil {
+		return x.Surt
+	}
+	return ""
+}
+
+func (x *CanonicalizeStreamResponse) GetHostAscii() string {
+	if x != nil {
+		return x.HostAscii
+	}
+	return ""
+}
+
+func (x *CanonicalizeStreamResponse) GetHostUnicode() string {
+	if x != nil {
+		return x.HostUnicode
+	}
+	return ""
+}
+
+func (x *CanonicalizeStreamResponse) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *CanonicalizeStreamResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_scopeservice_v1_scopeservice_proto protoreflect.FileDescriptor
+
+var file_scopeservice_v1_scopeservice_proto_rawDesc = []byte{
+	0x0a, 0x22, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2f, 0x76,
+	0x31, 0x2f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x19, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x1a,
+	0x1a, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x73, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1b, 0x66, 0x72, 0x6f,
+	0x6e, 0x74, 0x69, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x22, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2f, 0x76, 0x31, 0x2f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x63,
+	0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc2, 0x01, 0x0a,
+	0x16, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x43, 0x0a, 0x0a, 0x71, 0x75, 0x65, 0x75, 0x65,
+	0x64, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x76, 0x65,
+	0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x66, 0x72, 0x6f, 0x6e,
+	0x74, 0x69, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x64, 0x55, 0x72,
+	0x69, 0x52, 0x09, 0x71, 0x75, 0x65, 0x75, 0x65, 0x64, 0x55, 0x72, 0x69, 0x12, 0x2a, 0x0a, 0x11,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x63, 0x6f, 0x70,
+	0x65, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x64,
+	0x65, 0x62, 0x75, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x64, 0x65, 0x62, 0x75,
+	0x67, 0x22, 0x68, 0x0a, 0x17, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31,
+	0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x63, 0x6f, 0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x43, 0x0a, 0x15, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x22, 0x18, 0x0a, 0x16, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xb9, 0x01, 0x0a, 0x0f, 0x45,
+	0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d,
+	0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x31, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31,
+	0x2e, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3b, 0x0a,
+	0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x76,
+	0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x05, 0x74, 0x72, 0x61, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65,
+	0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x65,
+	0x63, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0xcb, 0x02, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x63, 0x65,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6f, 0x6c,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x61,
+	0x72, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x61, 0x72, 0x67, 0x73, 0x12,
+	0x3d, 0x0a, 0x06, 0x6b, 0x77, 0x61, 0x72, 0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x25, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70,
+	0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63,
+	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x6b, 0x77, 0x61, 0x72, 0x67, 0x73, 0x12, 0x3d,
+	0x0a, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25,
+	0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x06, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x19, 0x0a,
+	0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x05,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x88, 0x01, 0x01, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x22, 0x36, 0x0a, 0x0a, 0x54, 0x72, 0x61, 0x63, 0x65, 0x56, 0x61, 0x6c,
+	0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x8d, 0x01, 0x0a,
+	0x15, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x5f,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x5f, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x53,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x61,
+	0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65, 0x79, 0x22, 0x75, 0x0a, 0x16,
+	0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x12, 0x45, 0x0a, 0x0a,
+	0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x25, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x69, 0x61,
+	0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x52, 0x0a, 0x64, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73,
+	0x74, 0x69, 0x63, 0x22, 0xd0, 0x01, 0x0a, 0x0a, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74,
+	0x69, 0x63, 0x12, 0x4a, 0x0a, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x2e, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e,
+	0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x69, 0x61, 0x67, 0x6e, 0x6f, 0x73, 0x74, 0x69, 0x63, 0x2e, 0x53, 0x65, 0x76, 0x65,
+	0x72, 0x69, 0x74, 0x79, 0x52, 0x08, 0x73, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x69,
+	0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x6f, 0x6c, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x03, 0x63, 0x6f, 0x6c, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x22, 0x0a, 0x08, 0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79, 0x12, 0x09,
+	0x0a, 0x05, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x57, 0x41, 0x52,
+	0x4e, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x22, 0x2d, 0x0a, 0x19, 0x43, 0x61, 0x6e, 0x6f, 0x6e, 0x69,
+	0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x75, 0x72, 0x69, 0x22, 0xf8, 0x01, 0x0a, 0x1a, 0x43, 0x61, 0x6e, 0x6f, 0x6e, 0x69,
+	0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x69, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x75, 0x72, 0x69, 0x12, 0x48, 0x0a, 0x0d, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69,
+	0x63, 0x61, 0x6c, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e,
+	0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x63, 0x6f,
+	0x6d, 0x6d, 0x6f, 0x6e, 0x73, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61, 0x72, 0x73, 0x65, 0x64, 0x55,
+	0x72, 0x69, 0x52, 0x0c, 0x63, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x55, 0x72, 0x69,
+	0x12, 0x12, 0x0a, 0x04, 0x73, 0x75, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x73, 0x75, 0x72, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x61, 0x73, 0x63,
+	0x69, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x68, 0x6f, 0x73, 0x74, 0x41, 0x73,
+	0x63, 0x69, 0x69, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x75, 0x6e, 0x69, 0x63,
+	0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x68, 0x6f, 0x73, 0x74, 0x55,
+	0x6e, 0x69, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72,
+	0x32, 0x96, 0x01, 0x0a, 0x18, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x65,
+	0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x7a, 0x0a,
+	0x0f, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x31, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f,
+	0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x63, 0x6f, 0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x8d, 0x01, 0x0a, 0x12, 0x53, 0x63,
+	0x6f, 0x70, 0x65, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x12, 0x77, 0x0a, 0x0e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x64, 0x75,
+	0x6c, 0x65, 0x12, 0x30, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73,
+	0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e,
+	0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x65, 0x72, 0x4d, 0x6f, 0x64, 0x75, 0x6c, 0x65, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x80, 0x01, 0x0a, 0x13, 0x53, 0x63,
+	0x6f, 0x70, 0x65, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x12, 0x69, 0x0a, 0x07, 0x45, 0x78, 0x70, 0x6c, 0x61, 0x69, 0x6e, 0x12, 0x30, 0x2e, 0x76,
+	0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x65, 0x72, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x63, 0x6f,
+	0x70, 0x65, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a,
+	0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x78, 0x70, 0x6c, 0x61,
+	0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0x8f, 0x01, 0x0a,
+	0x14, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x77, 0x0a, 0x0e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74,
+	0x65, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x30, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d,
+	0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x76, 0x65, 0x69, 0x64,
+	0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x53, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x32, 0xa8,
+	0x01, 0x0a, 0x1c, 0x55, 0x72, 0x69, 0x43, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x69,
+	0x7a, 0x65, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x87, 0x01, 0x0a, 0x12, 0x43, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x34, 0x2e, 0x76, 0x65, 0x69, 0x64, 0x65, 0x6d, 0x61,
+	0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x35, 0x2e, 0x76,
+	0x65, 0x69, 0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2e, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x61, 0x6e, 0x6f, 0x6e, 0x69, 0x63,
+	0x61, 0x6c, 0x69, 0x7a, 0x65, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x42, 0x39, 0x5a, 0x37, 0x76, 0x65, 0x69,
+	0x64, 0x65, 0x6d, 0x61, 0x6e, 0x6e, 0x2d, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_scopeservice_v1_scopeservice_proto_rawDescOnce sync.Once
+	file_scopeservice_v1_scopeservice_proto_rawDescData = file_scopeservice_v1_scopeservice_proto_rawDesc
+)
+
+func file_scopeservice_v1_scopeservice_proto_rawDescGZIP() []byte {
+	file_scopeservice_v1_scopeservice_proto_rawDescOnce.Do(func() {
+		file_scopeservice_v1_scopeservice_proto_rawDescData = protoimpl.X.CompressGZIP(file_scopeservice_v1_scopeservice_proto_rawDescData)
+	})
+	return file_scopeservice_v1_scopeservice_proto_rawDescData
+}
+
+var file_scopeservice_v1_scopeservice_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_scopeservice_v1_scopeservice_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_scopeservice_v1_scopeservice_proto_goTypes = []any{
+	(Diagnostic_Severity)(0),           // 0: veidemann.scopeservice.v1.Diagnostic.Severity
+	(*ScopeCheckBatchRequest)(nil),     // 1: veidemann.scopeservice.v1.ScopeCheckBatchRequest
+	(*ScopeCheckBatchResponse)(nil),    // 2: veidemann.scopeservice.v1.ScopeCheckBatchResponse
+	(*RegisterModuleRequest)(nil),      // 3: veidemann.scopeservice.v1.RegisterModuleRequest
+	(*RegisterModuleResponse)(nil),     // 4: veidemann.scopeservice.v1.RegisterModuleResponse
+	(*ExplainResponse)(nil),            // 5: veidemann.scopeservice.v1.ExplainResponse
+	(*TraceEntry)(nil),                 // 6: veidemann.scopeservice.v1.TraceEntry
+	(*TraceValue)(nil),                 // 7: veidemann.scopeservice.v1.TraceValue
+	(*ValidateScriptRequest)(nil),      // 8: veidemann.scopeservice.v1.ValidateScriptRequest
+	(*ValidateScriptResponse)(nil),     // 9: veidemann.scopeservice.v1.ValidateScriptResponse
+	(*Diagnostic)(nil),                 // 10: veidemann.scopeservice.v1.Diagnostic
+	(*CanonicalizeStreamRequest)(nil),  // 11: veidemann.scopeservice.v1.CanonicalizeStreamRequest
+	(*CanonicalizeStreamResponse)(nil), // 12: veidemann.scopeservice.v1.CanonicalizeStreamResponse
+	(*v1.QueuedUri)(nil),               // 13: veidemann.api.frontier.v1.QueuedUri
+	(*v11.ScopeCheckResponse)(nil),     // 14: veidemann.api.scopechecker.v1.ScopeCheckResponse
+	(*v12.ParsedUri)(nil),              // 15: veidemann.api.commons.v1.ParsedUri
+	(*v11.ScopeCheckRequest)(nil),      // 16: veidemann.api.scopechecker.v1.ScopeCheckRequest
+}
+var file_scopeservice_v1_scopeservice_proto_depIdxs = []int32{
+	13, // 0: veidemann.scopeservice.v1.ScopeCheckBatchRequest.queued_uri:type_name -> veidemann.api.frontier.v1.QueuedUri
+	14, // 1: veidemann.scopeservice.v1.ScopeCheckBatchResponse.response:type_name -> veidemann.api.scopechecker.v1.ScopeCheckResponse
+	14, // 2: veidemann.scopeservice.v1.ExplainResponse.response:type_name -> veidemann.api.scopechecker.v1.ScopeCheckResponse
+	6,  // 3: veidemann.scopeservice.v1.ExplainResponse.trace:type_name -> veidemann.scopeservice.v1.TraceEntry
+	7,  // 4: veidemann.scopeservice.v1.TraceEntry.kwargs:type_name -> veidemann.scopeservice.v1.TraceValue
+	7,  // 5: veidemann.scopeservice.v1.TraceEntry.values:type_name -> veidemann.scopeservice.v1.TraceValue
+	10, // 6: veidemann.scopeservice.v1.ValidateScriptResponse.diagnostic:type_name -> veidemann.scopeservice.v1.Diagnostic
+	0,  // 7: veidemann.scopeservice.v1.Diagnostic.severity:type_name -> veidemann.scopeservice.v1.Diagnostic.Severity
+	15, // 8: veidemann.scopeservice.v1.CanonicalizeStreamResponse.canonical_uri:type_name -> veidemann.api.commons.v1.ParsedUri
+	1,  // 9: veidemann.scopeservice.v1.ScopeCheckerBatchService.ScopeCheckBatch:input_type -> veidemann.scopeservice.v1.ScopeCheckBatchRequest
+	3,  // 10: veidemann.scopeservice.v1.ScopeModuleService.RegisterModule:input_type -> veidemann.scopeservice.v1.RegisterModuleRequest
+	16, // 11: veidemann.scopeservice.v1.ScopeExplainService.Explain:input_type -> veidemann.api.scopechecker.v1.ScopeCheckRequest
+	8,  // 12: veidemann.scopeservice.v1.ScopeValidateService.ValidateScript:input_type -> veidemann.scopeservice.v1.ValidateScriptRequest
+	11, // 13: veidemann.scopeservice.v1.UriCanonicalizerBatchService.CanonicalizeStream:input_type -> veidemann.scopeservice.v1.CanonicalizeStreamRequest
+	2,  // 14: veidemann.scopeservice.v1.ScopeCheckerBatchService.ScopeCheckBatch:output_type -> veidemann.scopeservice.v1.ScopeCheckBatchResponse
+	4,  // 15: veidemann.scopeservice.v1.ScopeModuleService.RegisterModule:output_type -> veidemann.scopeservice.v1.RegisterModuleResponse
+	5,  // 16: veidemann.scopeservice.v1.ScopeExplainService.Explain:output_type -> veidemann.scopeservice.v1.ExplainResponse
+	9,  // 17: veidemann.scopeservice.v1.ScopeValidateService.ValidateScript:output_type -> veidemann.scopeservice.v1.ValidateScriptResponse
+	12, // 18: veidemann.scopeservice.v1.UriCanonicalizerBatchService.CanonicalizeStream:output_type -> veidemann.scopeservice.v1.CanonicalizeStreamResponse
+	14, // [14:19] is the sub-list for method output_type
+	9,  // [9:14] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_scopeservice_v1_scopeservice_proto_init() }
+func file_scopeservice_v1_scopeservice_proto_init() {
+	if File_scopeservice_v1_scopeservice_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_scopeservice_v1_scopeservice_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*ScopeCheckBatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ScopeCheckBatchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*RegisterModuleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*RegisterModuleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ExplainResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*TraceEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*TraceValue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*ValidateScriptRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ValidateScriptResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*Diagnostic); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*CanonicalizeStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_scopeservice_v1_scopeservice_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CanonicalizeStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_scopeservice_v1_scopeservice_proto_msgTypes[5].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_scopeservice_v1_scopeservice_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   5,
+		},
+		GoTypes:           file_scopeservice_v1_scopeservice_proto_goTypes,
+		DependencyIndexes: file_scopeservice_v1_scopeservice_proto_depIdxs,
+		EnumInfos:         file_scopeservice_v1_scopeservice_proto_enumTypes,
+		MessageInfos:      file_scopeservice_v1_scopeservice_proto_msgTypes,
+	}.Build()
+	File_scopeservice_v1_scopeservice_proto = out.File
+	file_scopeservice_v1_scopeservice_proto_rawDesc = nil
+	file_scopeservice_v1_scopeservice_proto_goTypes = nil
+	file_scopeservice_v1_scopeservice_proto_depIdxs = nil
+}
@@ -0,0 +1,526 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.2
+// source: scopeservice/v1/scopeservice.proto
+
+package scopeservice
+
+import (
+	context "context"
+	v1 "github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ScopeCheckerBatchService_ScopeCheckBatch_FullMethodName = "/veidemann.scopeservice.v1.ScopeCheckerBatchService/ScopeCheckBatch"
+)
+
+// ScopeCheckerBatchServiceClient is the client API for ScopeCheckerBatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScopeCheckerBatchServiceClient interface {
+	ScopeCheckBatch(ctx context.Context, in *ScopeCheckBatchRequest, opts ...grpc.CallOption) (*ScopeCheckBatchResponse, error)
+}
+
+type scopeCheckerBatchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScopeCheckerBatchServiceClient(cc grpc.ClientConnInterface) ScopeCheckerBatchServiceClient {
+	return &scopeCheckerBatchServiceClient{cc}
+}
+
+func (c *scopeCheckerBatchServiceClient) ScopeCheckBatch(ctx context.Context, in *ScopeCheckBatchRequest, opts ...grpc.CallOption) (*ScopeCheckBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ScopeCheckBatchResponse)
+	err := c.cc.Invoke(ctx, ScopeCheckerBatchService_ScopeCheckBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScopeCheckerBatchServiceServer is the server API for ScopeCheckerBatchService service.
+// All implementations must embed UnimplementedScopeCheckerBatchServiceServer
+// for forward compatibility.
+type ScopeCheckerBatchServiceServer interface {
+	ScopeCheckBatch(context.Context, *ScopeCheckBatchRequest) (*ScopeCheckBatchResponse, error)
+	mustEmbedUnimplementedScopeCheckerBatchServiceServer()
+}
+
+// UnimplementedScopeCheckerBatchServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScopeCheckerBatchServiceServer struct{}
+
+func (UnimplementedScopeCheckerBatchServiceServer) ScopeCheckBatch(context.Context, *ScopeCheckBatchRequest) (*ScopeCheckBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScopeCheckBatch not implemented")
+}
+func (UnimplementedScopeCheckerBatchServiceServer) mustEmbedUnimplementedScopeCheckerBatchServiceServer() {
+}
+func (UnimplementedScopeCheckerBatchServiceServer) testEmbeddedByValue() {}
+
+// UnsafeScopeCheckerBatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScopeCheckerBatchServiceServer will
+// result in compilation errors.
+type UnsafeScopeCheckerBatchServiceServer interface {
+	mustEmbedUnimplementedScopeCheckerBatchServiceServer()
+}
+
+func RegisterScopeCheckerBatchServiceServer(s grpc.ServiceRegistrar, srv ScopeCheckerBatchServiceServer) {
+	// If the following call pancis, it indicates UnimplementedScopeCheckerBatchServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ScopeCheckerBatchService_ServiceDesc, srv)
+}
+
+func _ScopeCheckerBatchService_ScopeCheckBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScopeCheckBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeCheckerBatchServiceServer).ScopeCheckBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScopeCheckerBatchService_ScopeCheckBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeCheckerBatchServiceServer).ScopeCheckBatch(ctx, req.(*ScopeCheckBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ScopeCheckerBatchService_ServiceDesc is the grpc.ServiceDesc for ScopeCheckerBatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScopeCheckerBatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "veidemann.scopeservice.v1.ScopeCheckerBatchService",
+	HandlerType: (*ScopeCheckerBatchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ScopeCheckBatch",
+			Handler:    _ScopeCheckerBatchService_ScopeCheckBatch_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "scopeservice/v1/scopeservice.proto",
+}
+
+const (
+	ScopeModuleService_RegisterModule_FullMethodName = "/veidemann.scopeservice.v1.ScopeModuleService/RegisterModule"
+)
+
+// ScopeModuleServiceClient is the client API for ScopeModuleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScopeModuleServiceClient interface {
+	RegisterModule(ctx context.Context, in *RegisterModuleRequest, opts ...grpc.CallOption) (*RegisterModuleResponse, error)
+}
+
+type scopeModuleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScopeModuleServiceClient(cc grpc.ClientConnInterface) ScopeModuleServiceClient {
+	return &scopeModuleServiceClient{cc}
+}
+
+func (c *scopeModuleServiceClient) RegisterModule(ctx context.Context, in *RegisterModuleRequest, opts ...grpc.CallOption) (*RegisterModuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterModuleResponse)
+	err := c.cc.Invoke(ctx, ScopeModuleService_RegisterModule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScopeModuleServiceServer is the server API for ScopeModuleService service.
+// All implementations must embed UnimplementedScopeModuleServiceServer
+// for forward compatibility.
+type ScopeModuleServiceServer interface {
+	RegisterModule(context.Context, *RegisterModuleRequest) (*RegisterModuleResponse, error)
+	mustEmbedUnimplementedScopeModuleServiceServer()
+}
+
+// UnimplementedScopeModuleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScopeModuleServiceServer struct{}
+
+func (UnimplementedScopeModuleServiceServer) RegisterModule(context.Context, *RegisterModuleRequest) (*RegisterModuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterModule not implemented")
+}
+func (UnimplementedScopeModuleServiceServer) mustEmbedUnimplementedScopeModuleServiceServer() {}
+func (UnimplementedScopeModuleServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeScopeModuleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScopeModuleServiceServer will
+// result in compilation errors.
+type UnsafeScopeModuleServiceServer interface {
+	mustEmbedUnimplementedScopeModuleServiceServer()
+}
+
+func RegisterScopeModuleServiceServer(s grpc.ServiceRegistrar, srv ScopeModuleServiceServer) {
+	// If the following call pancis, it indicates UnimplementedScopeModuleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ScopeModuleService_ServiceDesc, srv)
+}
+
+func _ScopeModuleService_RegisterModule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterModuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeModuleServiceServer).RegisterModule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScopeModuleService_RegisterModule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeModuleServiceServer).RegisterModule(ctx, req.(*RegisterModuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ScopeModuleService_ServiceDesc is the grpc.ServiceDesc for ScopeModuleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScopeModuleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "veidemann.scopeservice.v1.ScopeModuleService",
+	HandlerType: (*ScopeModuleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterModule",
+			Handler:    _ScopeModuleService_RegisterModule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "scopeservice/v1/scopeservice.proto",
+}
+
+const (
+	ScopeExplainService_Explain_FullMethodName = "/veidemann.scopeservice.v1.ScopeExplainService/Explain"
+)
+
+// ScopeExplainServiceClient is the client API for ScopeExplainService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScopeExplainServiceClient interface {
+	Explain(ctx context.Context, in *v1.ScopeCheckRequest, opts ...grpc.CallOption) (*ExplainResponse, error)
+}
+
+type scopeExplainServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScopeExplainServiceClient(cc grpc.ClientConnInterface) ScopeExplainServiceClient {
+	return &scopeExplainServiceClient{cc}
+}
+
+func (c *scopeExplainServiceClient) Explain(ctx context.Context, in *v1.ScopeCheckRequest, opts ...grpc.CallOption) (*ExplainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExplainResponse)
+	err := c.cc.Invoke(ctx, ScopeExplainService_Explain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScopeExplainServiceServer is the server API for ScopeExplainService service.
+// All implementations must embed UnimplementedScopeExplainServiceServer
+// for forward compatibility.
+type ScopeExplainServiceServer interface {
+	Explain(context.Context, *v1.ScopeCheckRequest) (*ExplainResponse, error)
+	mustEmbedUnimplementedScopeExplainServiceServer()
+}
+
+// UnimplementedScopeExplainServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScopeExplainServiceServer struct{}
+
+func (UnimplementedScopeExplainServiceServer) Explain(context.Context, *v1.ScopeCheckRequest) (*ExplainResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Explain not implemented")
+}
+func (UnimplementedScopeExplainServiceServer) mustEmbedUnimplementedScopeExplainServiceServer() {}
+func (UnimplementedScopeExplainServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeScopeExplainServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScopeExplainServiceServer will
+// result in compilation errors.
+type UnsafeScopeExplainServiceServer interface {
+	mustEmbedUnimplementedScopeExplainServiceServer()
+}
+
+func RegisterScopeExplainServiceServer(s grpc.ServiceRegistrar, srv ScopeExplainServiceServer) {
+	// If the following call pancis, it indicates UnimplementedScopeExplainServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ScopeExplainService_ServiceDesc, srv)
+}
+
+func _ScopeExplainService_Explain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(v1.ScopeCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeExplainServiceServer).Explain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScopeExplainService_Explain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeExplainServiceServer).Explain(ctx, req.(*v1.ScopeCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ScopeExplainService_ServiceDesc is the grpc.ServiceDesc for ScopeExplainService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScopeExplainService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "veidemann.scopeservice.v1.ScopeExplainService",
+	HandlerType: (*ScopeExplainServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Explain",
+			Handler:    _ScopeExplainService_Explain_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "scopeservice/v1/scopeservice.proto",
+}
+
+const (
+	ScopeValidateService_ValidateScript_FullMethodName = "/veidemann.scopeservice.v1.ScopeValidateService/ValidateScript"
+)
+
+// ScopeValidateServiceClient is the client API for ScopeValidateService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScopeValidateServiceClient interface {
+	ValidateScript(ctx context.Context, in *ValidateScriptRequest, opts ...grpc.CallOption) (*ValidateScriptResponse, error)
+}
+
+type scopeValidateServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScopeValidateServiceClient(cc grpc.ClientConnInterface) ScopeValidateServiceClient {
+	return &scopeValidateServiceClient{cc}
+}
+
+func (c *scopeValidateServiceClient) ValidateScript(ctx context.Context, in *ValidateScriptRequest, opts ...grpc.CallOption) (*ValidateScriptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateScriptResponse)
+	err := c.cc.Invoke(ctx, ScopeValidateService_ValidateScript_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScopeValidateServiceServer is the server API for ScopeValidateService service.
+// All implementations must embed UnimplementedScopeValidateServiceServer
+// for forward compatibility.
+type ScopeValidateServiceServer interface {
+	ValidateScript(context.Context, *ValidateScriptRequest) (*ValidateScriptResponse, error)
+	mustEmbedUnimplementedScopeValidateServiceServer()
+}
+
+// UnimplementedScopeValidateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedScopeValidateServiceServer struct{}
+
+func (UnimplementedScopeValidateServiceServer) ValidateScript(context.Context, *ValidateScriptRequest) (*ValidateScriptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateScript not implemented")
+}
+func (UnimplementedScopeValidateServiceServer) mustEmbedUnimplementedScopeValidateServiceServer() {}
+func (UnimplementedScopeValidateServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeScopeValidateServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScopeValidateServiceServer will
+// result in compilation errors.
+type UnsafeScopeValidateServiceServer interface {
+	mustEmbedUnimplementedScopeValidateServiceServer()
+}
+
+func RegisterScopeValidateServiceServer(s grpc.ServiceRegistrar, srv ScopeValidateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedScopeValidateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ScopeValidateService_ServiceDesc, srv)
+}
+
+func _ScopeValidateService_ValidateScript_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateScriptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScopeValidateServiceServer).ValidateScript(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScopeValidateService_ValidateScript_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScopeValidateServiceServer).ValidateScript(ctx, req.(*ValidateScriptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ScopeValidateService_ServiceDesc is the grpc.ServiceDesc for ScopeValidateService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScopeValidateService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "veidemann.scopeservice.v1.ScopeValidateService",
+	HandlerType: (*ScopeValidateServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ValidateScript",
+			Handler:    _ScopeValidateService_ValidateScript_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "scopeservice/v1/scopeservice.proto",
+}
+
+const (
+	UriCanonicalizerBatchService_CanonicalizeStream_FullMethodName = "/veidemann.scopeservice.v1.UriCanonicalizerBatchService/CanonicalizeStream"
+)
+
+// UriCanonicalizerBatchServiceClient is the client API for UriCanonicalizerBatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UriCanonicalizerBatchServiceClient interface {
+	CanonicalizeStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CanonicalizeStreamRequest, CanonicalizeStreamResponse], error)
+}
+
+type uriCanonicalizerBatchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUriCanonicalizerBatchServiceClient(cc grpc.ClientConnInterface) UriCanonicalizerBatchServiceClient {
+	return &uriCanonicalizerBatchServiceClient{cc}
+}
+
+func (c *uriCanonicalizerBatchServiceClient) CanonicalizeStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[CanonicalizeStreamRequest, CanonicalizeStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &UriCanonicalizerBatchService_ServiceDesc.Streams[0], UriCanonicalizerBatchService_CanonicalizeStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[CanonicalizeStreamRequest, CanonicalizeStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UriCanonicalizerBatchService_CanonicalizeStreamClient = grpc.BidiStreamingClient[CanonicalizeStreamRequest, CanonicalizeStreamResponse]
+
+// UriCanonicalizerBatchServiceServer is the server API for UriCanonicalizerBatchService service.
+// All implementations must embed UnimplementedUriCanonicalizerBatchServiceServer
+// for forward compatibility.
+type UriCanonicalizerBatchServiceServer interface {
+	CanonicalizeStream(grpc.BidiStreamingServer[CanonicalizeStreamRequest, CanonicalizeStreamResponse]) error
+	mustEmbedUnimplementedUriCanonicalizerBatchServiceServer()
+}
+
+// UnimplementedUriCanonicalizerBatchServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUriCanonicalizerBatchServiceServer struct{}
+
+func (UnimplementedUriCanonicalizerBatchServiceServer) CanonicalizeStream(grpc.BidiStreamingServer[CanonicalizeStreamRequest, CanonicalizeStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method CanonicalizeStream not implemented")
+}
+func (UnimplementedUriCanonicalizerBatchServiceServer) mustEmbedUnimplementedUriCanonicalizerBatchServiceServer() {
+}
+func (UnimplementedUriCanonicalizerBatchServiceServer) testEmbeddedByValue() {}
+
+// UnsafeUriCanonicalizerBatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UriCanonicalizerBatchServiceServer will
+// result in compilation errors.
+type UnsafeUriCanonicalizerBatchServiceServer interface {
+	mustEmbedUnimplementedUriCanonicalizerBatchServiceServer()
+}
+
+func RegisterUriCanonicalizerBatchServiceServer(s grpc.ServiceRegistrar, srv UriCanonicalizerBatchServiceServer) {
+	// If the following call pancis, it indicates UnimplementedUriCanonicalizerBatchServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UriCanonicalizerBatchService_ServiceDesc, srv)
+}
+
+func _UriCanonicalizerBatchService_CanonicalizeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UriCanonicalizerBatchServiceServer).CanonicalizeStream(&grpc.GenericServerStream[CanonicalizeStreamRequest, CanonicalizeStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type UriCanonicalizerBatchService_CanonicalizeStreamServer = grpc.BidiStreamingServer[CanonicalizeStreamRequest, CanonicalizeStreamResponse]
+
+// UriCanonicalizerBatchService_ServiceDesc is the grpc.ServiceDesc for UriCanonicalizerBatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UriCanonicalizerBatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "veidemann.scopeservice.v1.UriCanonicalizerBatchService",
+	HandlerType: (*UriCanonicalizerBatchServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CanonicalizeStream",
+			Handler:       _UriCanonicalizerBatchService_CanonicalizeStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "scopeservice/v1/scopeservice.proto",
+}
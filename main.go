@@ -3,7 +3,9 @@ package main
 import (
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
+	"veidemann-scopeservice/pkg/cli"
 	"veidemann-scopeservice/pkg/logger"
 	"veidemann-scopeservice/pkg/script"
 	"veidemann-scopeservice/pkg/server"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 
 	"strings"
 
@@ -19,13 +22,42 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "check":
+			os.Exit(cli.Check(os.Args[2:], os.Stdout, os.Stderr))
+		case "test":
+			os.Exit(cli.Test(os.Args[2:], os.Stdout, os.Stderr))
+		case "validate":
+			os.Exit(cli.Validate(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	pflag.String("interface", "", "interface the browser controller api listens to. No value means all interfaces.")
 	pflag.Int("port", 8080, "port the browser controller api listens to.")
 	pflag.Bool("include-fragment", false, "if true, do not remove fragment from URI during canonicalization.")
+	pflag.Uint64("script-max-steps", script.DefaultMaxExecutionSteps, "max number of Starlark computation steps for one evaluation of a scope script. Zero means no limit.")
+	pflag.Duration("script-timeout", script.DefaultExecutionTimeout, "max time for one evaluation of a scope script. Zero means no limit.")
+	pflag.String("public-suffix-list", "", "file with an updated Public Suffix List. No value means use the embedded list.")
+	pflag.String("tls-cert", "", "file with the TLS certificate of the api. No value means the api does not use TLS.")
+	pflag.String("tls-key", "", "file with the private key of the TLS certificate.")
+	pflag.String("tls-client-ca", "", "file with the CA certificates which client certificates are verified against.")
+	pflag.String("tls-client-auth", "", "client certificate verification, available values are none, request (verify if given) and require. No value means require if tls-client-ca is set.")
+	pflag.Bool("grpc-reflection", false, "if true, register the gRPC reflection service, e.g. for grpcurl.")
+	pflag.Int("batch-workers", runtime.NumCPU(), "number of workers evaluating URIs in a batch scope check.")
+	pflag.StringSlice("strip-params", nil, "query and path parameters removed by the built-in canonicalization profiles. Exact names, prefixes ending with '*' or regular expressions enclosed in '/'.")
+	pflag.String("canonicalization-profiles", "", "YAML or JSON file with named canonicalization profiles.")
+	pflag.String("script-module-dir", "", "directory with modules scope scripts can load. No value means only modules registered with the api can be loaded.")
+	pflag.String("script-registry-dir", "", "directory with named scope scripts which are used when a request has a script name, but no script. The directory is watched for changes.")
+	pflag.String("script-list-dir", "", "directory with host (.hosts), domain (.domains) and URL prefix (.prefixes) lists scope scripts can look up values in with inList(). The directory is watched for changes.")
+	pflag.String("status-codes", "", "YAML or JSON file with custom status codes, e.g. 'OutOfCollectionScope: -5020'.")
+	pflag.Int("script-cache-size", script.DefaultProgramCacheSize, "max number of compiled scope scripts to cache. Zero disables the cache.")
 
 	pflag.String("metrics-interface", "", "Interface for exposing metrics. Empty means all interfaces")
 	pflag.Int("metrics-port", 9153, "Port for exposing metrics")
 	pflag.String("metrics-path", "/metrics", "Path for exposing metrics")
+	pflag.StringSlice("metrics-script-allowlist", nil, "script names used as label in metrics. Other scripts are labeled 'other'. No value means the first metrics-max-scripts names are used")
+	pflag.Int("metrics-max-scripts", telemetry.DefaultMaxScriptLabels, "max number of distinct script names used as label in metrics. Zero means no limit")
 
 	pflag.String("log-level", "info", "log level, available levels are panic, fatal, error, warn, info, debug and trace")
 	pflag.String("log-formatter", "logfmt", "log formatter, available values are logfmt and json")
@@ -45,9 +77,36 @@ func main() {
 
 	logger.InitLog(viper.GetString("log-level"), viper.GetString("log-formatter"), viper.GetBool("log-method"))
 
-	script.InitializeCanonicalizationProfiles(viper.GetBool("include-fragment"))
-	scopeservice := server.New(viper.GetString("interface"), viper.GetInt("port"))
+	// script labels are used by the metrics recorded when scripts are compiled, e.g. by the script registry
+	telemetry.InitializeScriptLabels(viper.GetStringSlice("metrics-script-allowlist"), viper.GetInt("metrics-max-scripts"))
 
+	if err := script.InitializeCanonicalizationProfiles(viper.GetBool("include-fragment"), viper.GetStringSlice("strip-params")...); err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize canonicalization profiles")
+	}
+	if profiles := viper.GetString("canonicalization-profiles"); profiles != "" {
+		if err := script.LoadCanonicalizationProfiles(profiles); err != nil {
+			log.Fatal().Err(err).Msg("Could not load canonicalization profiles")
+		}
+	}
+	if codes := viper.GetString("status-codes"); codes != "" {
+		if err := script.LoadStatusCodes(codes); err != nil {
+			log.Fatal().Err(err).Msg("Could not load status codes")
+		}
+	}
+	script.InitializeProgramCache(viper.GetInt("script-cache-size"))
+	script.InitializeModules(viper.GetString("script-module-dir"))
+	if err := script.InitializeScriptRegistry(viper.GetString("script-registry-dir")); err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize script registry")
+	}
+	if err := script.InitializeLists(viper.GetString("script-list-dir")); err != nil {
+		log.Fatal().Err(err).Msg("Could not initialize scope lists")
+	}
+	script.InitializeExecutionLimits(viper.GetUint64("script-max-steps"), viper.GetDuration("script-timeout"))
+	if psl := viper.GetString("public-suffix-list"); psl != "" {
+		if err := script.LoadPublicSuffixList(psl); err != nil {
+			log.Fatal().Err(err).Msg("Could not load public suffix list")
+		}
+	}
 	// telemetry setup
 	tracer, closer := telemetry.InitTracer("Scope checker")
 	if tracer != nil {
@@ -55,6 +114,21 @@ func main() {
 		defer closer.Close()
 	}
 
+	var serverOpts []grpc.ServerOption
+	if cert := viper.GetString("tls-cert"); cert != "" {
+		creds, err := server.TLSCredentials(server.TLSConfig{
+			CertFile:     cert,
+			KeyFile:      viper.GetString("tls-key"),
+			ClientCAFile: viper.GetString("tls-client-ca"),
+			ClientAuth:   viper.GetString("tls-client-auth"),
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Could not configure TLS")
+		}
+		serverOpts = append(serverOpts, grpc.Creds(creds))
+	}
+	scopeservice := server.New(viper.GetString("interface"), viper.GetInt("port"), viper.GetInt("batch-workers"), viper.GetBool("grpc-reflection"), serverOpts...)
+
 	errc := make(chan error, 1)
 
 	ms := telemetry.NewMetricsServer(viper.GetString("metrics-interface"), viper.GetInt("metrics-port"), viper.GetString("metrics-path"))
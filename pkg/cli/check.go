@@ -0,0 +1,255 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"veidemann-scopeservice/pkg/script"
+
+	"github.com/nlnwa/veidemann-api/go/commons/v1"
+	"github.com/nlnwa/veidemann-api/go/config/v1"
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/spf13/pflag"
+)
+
+// Exit codes returned by the subcommands.
+const (
+	ExitOK       = 0
+	ExitMismatch = 1
+	ExitError    = 2
+)
+
+// checkResult is the result of checking one URI. It is also the format of JSONL output.
+type checkResult struct {
+	Uri        string         `json:"uri"`
+	Evaluation string         `json:"evaluation"`
+	Status     string         `json:"status"`
+	Code       int32          `json:"code"`
+	Console    string         `json:"console,omitempty"`
+	Error      *commons.Error `json:"error,omitempty"`
+}
+
+// Check runs a scope script locally for a list of URIs and prints the results. It returns ExitMismatch if the
+// results differ from the expected results and ExitError if the check could not be run.
+func Check(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := pflag.NewFlagSet("check", pflag.ContinueOnError)
+	flags.SetOutput(stderr)
+	scriptFile := flags.String("script", "", "file with the scope script to check.")
+	seed := flags.String("seed", "", "seed URI of the URIs to check.")
+	uriFile := flags.String("uri-file", "-", "file with one URI to check on each line, optionally followed by a discovery path. '-' means stdin.")
+	annotations := flags.StringArray("annotation", nil, "annotation available to the script with param(), given as key=value. Can be repeated.")
+	debug := flags.Bool("debug", false, "turn on debug output from the script.")
+	output := flags.String("output", "table", "output format, available values are table and jsonl.")
+	expected := flags.String("expected", "", "file with one URI and its expected status, e.g. 'Include' or 'Blocked', on each line.")
+	addScriptFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return ExitError
+	}
+	if *scriptFile == "" {
+		_, _ = fmt.Fprintln(stderr, "missing required flag --script")
+		return ExitError
+	}
+	if *output != "table" && *output != "jsonl" {
+		_, _ = fmt.Fprintf(stderr, "unknown output format '%s'\n", *output)
+		return ExitError
+	}
+	if err := initializeScript(flags); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+
+	src, err := os.ReadFile(*scriptFile)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+	annotation, err := parseAnnotations(*annotations)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+	uris, err := readLines(*uriFile)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+
+	compiled := script.CompileScopeScript(filepath.Base(*scriptFile), src)
+	var results []checkResult
+	for _, line := range uris {
+		fields := strings.Fields(line)
+		qUri := &frontier.QueuedUri{
+			Uri:        fields[0],
+			SeedUri:    *seed,
+			Annotation: annotation,
+		}
+		if len(fields) > 1 {
+			qUri.DiscoveryPath = fields[1]
+		}
+		r := compiled.Run(context.Background(), qUri, *debug)
+		results = append(results, checkResult{
+			Uri:        fields[0],
+			Evaluation: r.Evaluation.String(),
+			Status:     compiled.StatusName(r.ExcludeReason),
+			Code:       r.ExcludeReason,
+			Console:    r.Console,
+			Error:      r.Error,
+		})
+	}
+
+	if *output == "jsonl" {
+		err = writeJsonl(stdout, results)
+	} else {
+		err = writeTable(stdout, results)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+
+	if *expected == "" {
+		return ExitOK
+	}
+	want, err := readExpected(*expected, compiled.StatusByName)
+	if err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+	if mismatches := compareExpected(results, want); len(mismatches) > 0 {
+		for _, m := range mismatches {
+			_, _ = fmt.Fprintln(stderr, m)
+		}
+		return ExitMismatch
+	}
+	return ExitOK
+}
+
+// parseAnnotations converts key=value pairs to annotations.
+func parseAnnotations(kv []string) ([]*config.Annotation, error) {
+	var annotations []*config.Annotation
+	for _, a := range kv {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok {
+			return nil, fmt.Errorf("illegal annotation '%s', must be key=value", a)
+		}
+		annotations = append(annotations, &config.Annotation{Key: k, Value: v})
+	}
+	return annotations, nil
+}
+
+// readLines returns the lines of a file, skipping empty lines and lines starting with '#'. The name '-' means stdin.
+func readLines(name string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// readExpected reads lines of URIs and expected statuses. A status is a name like 'Blocked' or a status code.
+// Names are looked up with statusByName.
+func readExpected(name string, statusByName func(string) (script.Status, bool)) (map[string]script.Status, error) {
+	lines, err := readLines(name)
+	if err != nil {
+		return nil, err
+	}
+	want := make(map[string]script.Status, len(lines))
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("illegal line in %s: '%s', must be URI and status", name, line)
+		}
+		status, ok := statusByName(fields[1])
+		if !ok {
+			code, err := strconv.ParseInt(fields[1], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("unknown status '%s' in %s", fields[1], name)
+			}
+			status = script.Status(code)
+		}
+		want[fields[0]] = status
+	}
+	return want, nil
+}
+
+func compareExpected(results []checkResult, want map[string]script.Status) []string {
+	var mismatches []string
+	seen := make(map[string]bool, len(results))
+	for _, r := range results {
+		seen[r.Uri] = true
+		w, ok := want[r.Uri]
+		if !ok {
+			continue
+		}
+		if r.Code != w.AsInt32() {
+			mismatches = append(mismatches, fmt.Sprintf("%s: got %s (%d), want %s (%d)", r.Uri, r.Status, r.Code, w, w.AsInt32()))
+		}
+	}
+	for uri := range want {
+		if !seen[uri] {
+			mismatches = append(mismatches, fmt.Sprintf("%s: expected result, but URI was not checked", uri))
+		}
+	}
+	return mismatches
+}
+
+func writeJsonl(w io.Writer, results []checkResult) error {
+	enc := json.NewEncoder(w)
+	for _, r := range results {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable writes a table with one URI on each line followed by the console output and errors of each URI.
+func writeTable(w io.Writer, results []checkResult) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "URI\tEVALUATION\tSTATUS\tCODE")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", r.Uri, r.Evaluation, r.Status, r.Code)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		if r.Console == "" && r.Error == nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\n%s:\n", r.Uri)
+		for _, l := range strings.Split(strings.TrimSuffix(r.Console, "\n"), "\n") {
+			if l != "" {
+				_, _ = fmt.Fprintf(w, "  %s\n", l)
+			}
+		}
+		if r.Error != nil {
+			_, _ = fmt.Fprintf(w, "  error %d %s: %s\n", r.Error.Code, r.Error.Msg, strings.ReplaceAll(r.Error.Detail, "\n", "\n    "))
+		}
+	}
+	return nil
+}
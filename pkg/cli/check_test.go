@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCheck(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"scope.star": "isScheme('http https').otherwise(ChaffDetection)\n" +
+			"maxHopsFromSeed(param('maxHops')).then(TooManyHops)\n" +
+			"isSameHost().then(Include)\n",
+		"uris.txt": "# comment\n" +
+			"http://www.example.com/a\n" +
+			"http://www.example.com/b LLL\n" +
+			"ftp://www.example.com/c\n" +
+			"http://www.other.com/d\n",
+		"expected.txt": "http://www.example.com/a Include\n" +
+			"http://www.example.com/b TooManyHops\n" +
+			"ftp://www.example.com/c -4000\n" +
+			"http://www.other.com/d Blocked\n",
+		"wrong.txt": "http://www.example.com/a Blocked\n" +
+			"http://www.example.com/e Include\n",
+		"loop.star": "def loop():\n  for i in range(1000000000):\n    pass\nloop()\n",
+	})
+	args := []string{
+		"--script", filepath.Join(dir, "scope.star"),
+		"--seed", "http://www.example.com/",
+		"--uri-file", filepath.Join(dir, "uris.txt"),
+		"--annotation", "maxHops=2",
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{"table", args, ExitOK,
+			[]string{"URI                       EVALUATION  STATUS          CODE", "http://www.example.com/b  EXCLUDE     TooManyHops     -4001"}, nil},
+		{"expected", append(args, "--expected", filepath.Join(dir, "expected.txt")), ExitOK, nil, nil},
+		{"mismatch", append(args, "--expected", filepath.Join(dir, "wrong.txt")), ExitMismatch, nil,
+			[]string{"http://www.example.com/a: got Include (0), want Blocked (-5001)", "http://www.example.com/e: expected result, but URI was not checked"}},
+		{"debug", append(args, "--debug"), ExitOK,
+			[]string{"http://www.example.com/b:\n", "  scope.star:2:16 maxHopsFromSeed(\"2\") discoveryPath=LLL, hops=3, match=true"}, nil},
+		{"missing script", []string{"--uri-file", filepath.Join(dir, "uris.txt")}, ExitError, nil, []string{"missing required flag --script"}},
+		{"bad annotation", append(args, "--annotation", "foo"), ExitError, nil, []string{"illegal annotation 'foo'"}},
+		{"max steps", []string{"--script", filepath.Join(dir, "loop.star"), "--uri-file", filepath.Join(dir, "uris.txt"), "--script-max-steps", "1000"}, ExitOK,
+			[]string{"ScriptTimeout  -5003", "scope script exceeded max execution steps"}, nil},
+		{"timeout", []string{"--script", filepath.Join(dir, "loop.star"), "--uri-file", filepath.Join(dir, "uris.txt"), "--script-max-steps", "0", "--script-timeout", "50ms"}, ExitOK,
+			[]string{"ScriptTimeout  -5003", "scope script timed out"}, nil},
+		{"missing public suffix list", append(args, "--public-suffix-list", filepath.Join(dir, "missing.dat")), ExitError, nil, []string{"missing.dat"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := Check(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("Check() got = %v, want %v\nstderr: %s", got, tt.want, stderr.String())
+			}
+			for _, s := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("Check() stdout missing %q\nstdout:\n%s", s, stdout.String())
+				}
+			}
+			for _, s := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), s) {
+					t.Errorf("Check() stderr missing %q\nstderr:\n%s", s, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCheckJsonl(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"scope.star": "test(False).then(Include)\n",
+		"uris.txt":   "http://www.example.com/\n",
+	})
+	var stdout, stderr bytes.Buffer
+	args := []string{"--script", filepath.Join(dir, "scope.star"), "--uri-file", filepath.Join(dir, "uris.txt"), "--output", "jsonl"}
+	if got := Check(args, &stdout, &stderr); got != ExitOK {
+		t.Fatalf("Check() got = %v, want %v\nstderr: %s", got, ExitOK, stderr.String())
+	}
+	var result checkResult
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		t.Fatalf("Check() output is not JSON: %v", err)
+	}
+	if result.Uri != "http://www.example.com/" || result.Status != "Blocked" || result.Error == nil {
+		t.Errorf("Check() got = %+v", result)
+	}
+}
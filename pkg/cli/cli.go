@@ -0,0 +1,59 @@
+// Package cli implements the subcommands for working with scope scripts offline.
+package cli
+
+import (
+	"veidemann-scopeservice/pkg/script"
+
+	"github.com/spf13/pflag"
+)
+
+// addScriptFlags adds the flags configuring script evaluation the same way as in the service.
+func addScriptFlags(flags *pflag.FlagSet) {
+	flags.Bool("include-fragment", false, "if true, do not remove fragment from URI during canonicalization.")
+	flags.StringSlice("strip-params", nil, "query and path parameters removed by the built-in canonicalization profiles.")
+	flags.String("canonicalization-profiles", "", "YAML or JSON file with named canonicalization profiles.")
+	flags.String("script-module-dir", "", "directory with modules scope scripts can load.")
+	flags.String("script-list-dir", "", "directory with host, domain and URL prefix lists scope scripts can look up values in.")
+	flags.String("status-codes", "", "YAML or JSON file with custom status codes.")
+	flags.Uint64("script-max-steps", script.DefaultMaxExecutionSteps, "max number of Starlark computation steps for one evaluation of a scope script. Zero means no limit.")
+	flags.Duration("script-timeout", script.DefaultExecutionTimeout, "max time for one evaluation of a scope script. Zero means no limit.")
+	flags.String("public-suffix-list", "", "file with an updated Public Suffix List. No value means use the embedded list.")
+}
+
+// initializeScript configures script evaluation from the flags added by addScriptFlags.
+func initializeScript(flags *pflag.FlagSet) error {
+	includeFragment, _ := flags.GetBool("include-fragment")
+	stripParams, _ := flags.GetStringSlice("strip-params")
+	profiles, _ := flags.GetString("canonicalization-profiles")
+	moduleDir, _ := flags.GetString("script-module-dir")
+	listDir, _ := flags.GetString("script-list-dir")
+	statusCodes, _ := flags.GetString("status-codes")
+	maxSteps, _ := flags.GetUint64("script-max-steps")
+	timeout, _ := flags.GetDuration("script-timeout")
+	psl, _ := flags.GetString("public-suffix-list")
+
+	if err := script.InitializeCanonicalizationProfiles(includeFragment, stripParams...); err != nil {
+		return err
+	}
+	if profiles != "" {
+		if err := script.LoadCanonicalizationProfiles(profiles); err != nil {
+			return err
+		}
+	}
+	script.InitializeModules(moduleDir)
+	if err := script.InitializeLists(listDir); err != nil {
+		return err
+	}
+	if statusCodes != "" {
+		if err := script.LoadStatusCodes(statusCodes); err != nil {
+			return err
+		}
+	}
+	script.InitializeExecutionLimits(maxSteps, timeout)
+	if psl != "" {
+		if err := script.LoadPublicSuffixList(psl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
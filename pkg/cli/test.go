@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"veidemann-scopeservice/pkg/script"
+
+	"github.com/spf13/pflag"
+)
+
+const testFileSuffix = "_test.star"
+
+// Test runs the test functions in script test files and prints the results. It returns ExitMismatch if a test
+// failed and ExitError if the tests could not be run.
+func Test(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: veidemann-scopeservice test [flags] <name>_test.star ...")
+		flags.PrintDefaults()
+	}
+	scriptFile := flags.String("script", "", "file with the scope script to test. Defaults to <name>.star for a test file named <name>_test.star.")
+	verbose := flags.BoolP("verbose", "v", false, "print passed tests too.")
+	addScriptFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return ExitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return ExitError
+	}
+	if err := initializeScript(flags); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+
+	exitCode := ExitOK
+	for _, testFile := range flags.Args() {
+		target := *scriptFile
+		if target == "" {
+			if !strings.HasSuffix(testFile, testFileSuffix) {
+				_, _ = fmt.Fprintf(stderr, "cannot find script for %s, use --script\n", testFile)
+				return ExitError
+			}
+			target = strings.TrimSuffix(testFile, testFileSuffix) + ".star"
+		}
+
+		results, err := script.RunTestFile(target, testFile)
+		if err != nil {
+			_, _ = fmt.Fprintf(stderr, "%s: %v\n", testFile, err)
+			return ExitError
+		}
+		failed := 0
+		for _, r := range results {
+			if r.Passed() {
+				if *verbose {
+					_, _ = fmt.Fprintf(stdout, "--- PASS: %s\n", r.Name)
+				}
+				continue
+			}
+			failed++
+			_, _ = fmt.Fprintf(stdout, "--- FAIL: %s\n", r.Name)
+			for _, f := range r.Failures {
+				_, _ = fmt.Fprintf(stdout, "    %s\n", strings.ReplaceAll(f, "\n", "\n    "))
+			}
+		}
+		if failed > 0 {
+			exitCode = ExitMismatch
+			_, _ = fmt.Fprintf(stdout, "FAIL\t%s\t%d of %d tests failed\n", testFile, failed, len(results))
+		} else {
+			_, _ = fmt.Fprintf(stdout, "ok\t%s\t%d tests\n", testFile, len(results))
+		}
+	}
+	return exitCode
+}
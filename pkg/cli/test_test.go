@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTest(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"scope.star":       "isSameHost().then(Include)\n",
+		"scope_test.star":  "def test_same_host():\n    assertScope('http://a.com/b', seed='http://a.com/')\n",
+		"other_test.star":  "def test_other_host():\n    assertScope('http://b.com/', seed='http://a.com/')\n",
+		"broken_test.star": "def test_(:\n",
+	})
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{"pass", []string{filepath.Join(dir, "scope_test.star")}, ExitOK, []string{"ok\t", "1 tests"}, nil},
+		{"verbose", []string{"-v", filepath.Join(dir, "scope_test.star")}, ExitOK, []string{"--- PASS: test_same_host"}, nil},
+		{"fail", []string{"--script", filepath.Join(dir, "scope.star"), filepath.Join(dir, "other_test.star")}, ExitMismatch,
+			[]string{"--- FAIL: test_other_host", "got Blocked (-5001), want Include (0)", "1 of 1 tests failed"}, nil},
+		{"missing script", []string{filepath.Join(dir, "other_test.star")}, ExitError, nil, []string{"no such file"}},
+		{"broken test file", []string{"--script", filepath.Join(dir, "scope.star"), filepath.Join(dir, "broken_test.star")}, ExitError, nil, []string{"broken_test.star:1:"}},
+		{"no test files", nil, ExitError, nil, []string{"Usage:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := Test(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("Test() got = %v, want %v\nstdout: %s\nstderr: %s", got, tt.want, stdout.String(), stderr.String())
+			}
+			for _, s := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("Test() stdout missing %q\nstdout:\n%s", s, stdout.String())
+				}
+			}
+			for _, s := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), s) {
+					t.Errorf("Test() stderr missing %q\nstderr:\n%s", s, stderr.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"veidemann-scopeservice/pkg/script"
+
+	"github.com/spf13/pflag"
+)
+
+// Validate checks scope scripts without evaluating them and prints the problems found. It returns ExitMismatch
+// if any script has errors and ExitError if the scripts could not be read.
+func Validate(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := pflag.NewFlagSet("validate", pflag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		_, _ = fmt.Fprintln(stderr, "Usage: veidemann-scopeservice validate [flags] <script>.star ...")
+		flags.PrintDefaults()
+	}
+	annotationKeys := flags.StringArray("annotation-key", nil, "annotation available to the script with param(). If given, param() with other names is reported. Can be repeated.")
+	output := flags.String("output", "text", "output format, available values are text and jsonl.")
+	addScriptFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return ExitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return ExitError
+	}
+	if *output != "text" && *output != "jsonl" {
+		_, _ = fmt.Fprintf(stderr, "unknown output format '%s'\n", *output)
+		return ExitError
+	}
+	if err := initializeScript(flags); err != nil {
+		_, _ = fmt.Fprintln(stderr, err)
+		return ExitError
+	}
+	var annotations []string
+	if flags.Changed("annotation-key") {
+		annotations = *annotationKeys
+	}
+
+	exitCode := ExitOK
+	enc := json.NewEncoder(stdout)
+	for _, file := range flags.Args() {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			_, _ = fmt.Fprintln(stderr, err)
+			return ExitError
+		}
+		diagnostics := script.ValidateScript(file, src, annotations)
+		if script.HasErrors(diagnostics) {
+			exitCode = ExitMismatch
+		}
+		for _, d := range diagnostics {
+			if *output == "jsonl" {
+				err = enc.Encode(d)
+			} else {
+				_, err = fmt.Fprintln(stdout, d)
+			}
+			if err != nil {
+				_, _ = fmt.Fprintln(stderr, err)
+				return ExitError
+			}
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"valid.star":   "isSameHost().then(Include)\n",
+		"warning.star": "maxHopsFromSeed(param('hops')).then(TooManyHops)\nisScheme('http')\n",
+		"error.star":   "isSameHost().then(Inclde)\n",
+	})
+
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout []string
+		wantStderr []string
+	}{
+		{"valid", []string{filepath.Join(dir, "valid.star")}, ExitOK, nil, nil},
+		{"warning", []string{"--annotation-key", "scope_hops", filepath.Join(dir, "warning.star")}, ExitOK,
+			[]string{"warning.star:1:23: warning: param 'hops' is not in the annotations",
+				"warning.star:2:1: warning: result of isScheme() is not used"}, nil},
+		{"error", []string{filepath.Join(dir, "valid.star"), filepath.Join(dir, "error.star")}, ExitMismatch,
+			[]string{"error.star:1:19: error: unknown status Inclde in then()"}, nil},
+		{"jsonl", []string{"--output", "jsonl", filepath.Join(dir, "error.star")}, ExitMismatch,
+			[]string{`{"severity":"error",`, `"line":1,"col":19,"message":"unknown status Inclde in then()"`}, nil},
+		{"missing file", []string{filepath.Join(dir, "missing.star")}, ExitError, nil, []string{"no such file"}},
+		{"no files", nil, ExitError, nil, []string{"Usage:"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := Validate(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("Validate() got = %v, want %v\nstdout: %s\nstderr: %s", got, tt.want, stdout.String(), stderr.String())
+			}
+			if len(tt.wantStdout) == 0 && stdout.Len() > 0 {
+				t.Errorf("Validate() stdout got:\n%s\nwant empty", stdout.String())
+			}
+			for _, s := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), s) {
+					t.Errorf("Validate() stdout missing %q\nstdout:\n%s", s, stdout.String())
+				}
+			}
+			for _, s := range tt.wantStderr {
+				if !strings.Contains(stderr.String(), s) {
+					t.Errorf("Validate() stderr missing %q\nstderr:\n%s", s, stderr.String())
+				}
+			}
+		})
+	}
+}
@@ -67,12 +67,12 @@ func getStatus(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple
 }
 
 func setStatus(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
-	var status Status
+	status := statusArg{thread: thread}
 	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "status", &status); err != nil {
 		return nil, err
 	}
-	thread.SetLocal(resultKey, status)
-	printDebug(thread, b, args, kwargs, "status="+status.String())
+	thread.SetLocal(resultKey, status.status)
+	printDebugValues(thread, b, args, kwargs, debugValue{"status", statusName(thread, status.status)})
 	return starlark.None, nil
 }
 
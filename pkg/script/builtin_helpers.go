@@ -27,34 +27,96 @@ func stackTraceEnabled(thread *starlark.Thread) bool {
 	}
 }
 
+// errUrlNotAvailable is returned by builtins using the url when called without a url, e.g. from the top level
+// statements of a module.
+var errUrlNotAvailable = errors.New("url is not available in module top level statements")
+
+// threadUrl returns the url evaluated by thread.
+func threadUrl(thread *starlark.Thread) (*UrlValue, error) {
+	u, ok := thread.Local(urlKey).(*UrlValue)
+	if !ok {
+		return nil, errUrlNotAvailable
+	}
+	return u, nil
+}
+
+// debugValue is a named value computed by a builtin. The values are printed as name=value in the debug output and
+// recorded in the trace in explain mode. The values named 'match' and 'status' are the result of the builtin.
+type debugValue struct {
+	name  string
+	value interface{}
+}
+
+func (v debugValue) String() string {
+	if b, ok := v.value.(bool); ok {
+		return Match(b).String()
+	}
+	return fmt.Sprint(v.value)
+}
+
+// printDebugValues prints the values computed by a builtin if debug is enabled, and records them in the trace.
+func printDebugValues(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, values ...debugValue) {
+	funcName := builtinFuncName(b)
+	recordTrace(thread, funcName, args, kwargs, values, "")
+	if debugEnabled(thread) {
+		parts := make([]string, len(values))
+		for i, v := range values {
+			parts[i] = v.name + "=" + v.String()
+		}
+		printDebugLine(thread, funcName, args, kwargs, strings.Join(parts, ", "))
+	}
+}
+
+// printDebugValuesf is like printDebugValues, but formats the values with format in the debug output. It is used by
+// builtins whose debug output does not follow the name=value format of printDebugValues.
+func printDebugValuesf(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, format string, values ...debugValue) {
+	funcName := builtinFuncName(b)
+	recordTrace(thread, funcName, args, kwargs, values, "")
+	if debugEnabled(thread) {
+		a := make([]interface{}, len(values))
+		for i, v := range values {
+			a[i] = v.value
+		}
+		printDebugLine(thread, funcName, args, kwargs, fmt.Sprintf(format, a...))
+	}
+}
+
 func printDebugf(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, format string, a ...interface{}) {
 	msg := fmt.Sprintf(format, a...)
 	printDebug(thread, b, args, kwargs, msg)
 }
 
+// printDebug prints a message from a builtin if debug is enabled, and records it in the trace.
 func printDebug(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, msg string) {
+	funcName := builtinFuncName(b)
+	recordTrace(thread, funcName, args, kwargs, nil, msg)
 	if debugEnabled(thread) {
-		var funcName string
-		if b.Receiver() != nil {
-			funcName = fmt.Sprintf("%v.%v", b.Receiver().Type(), b.Name())
-		} else {
-			funcName = fmt.Sprintf("%v", b.Name())
-		}
-		m := fmt.Sprintf("%v(%v) %v", funcName, joinArgs(args, kwargs), msg)
-		if stackTraceEnabled(thread) {
-			m += "\n" + thread.CallStack().String()
-		}
-		thread.Print(thread, m)
+		printDebugLine(thread, funcName, args, kwargs, msg)
 	}
 }
 
-func joinArgs(a starlark.Tuple, k []starlark.Tuple) string {
+func builtinFuncName(b *starlark.Builtin) string {
+	if b.Receiver() != nil {
+		return fmt.Sprintf("%v.%v", b.Receiver().Type(), b.Name())
+	}
+	return b.Name()
+}
+
+func printDebugLine(thread *starlark.Thread, funcName string, args starlark.Tuple, kwargs []starlark.Tuple, msg string) {
+	m := fmt.Sprintf("%v(%v) %v", funcName, joinArgs(thread, args, kwargs), msg)
+	if stackTraceEnabled(thread) {
+		m += "\n" + thread.CallStack().String()
+	}
+	thread.Print(thread, m)
+}
+
+func joinArgs(thread *starlark.Thread, a starlark.Tuple, k []starlark.Tuple) string {
 	var b strings.Builder
 	if len(a) > 0 {
-		b.WriteString(a[0].String())
+		b.WriteString(argString(thread, a[0]))
 		for _, s := range a[1:] {
 			b.WriteString(", ")
-			b.WriteString(s.String())
+			b.WriteString(argString(thread, s))
 		}
 	}
 
@@ -62,16 +124,24 @@ func joinArgs(a starlark.Tuple, k []starlark.Tuple) string {
 		if len(a) > 0 {
 			b.WriteString(", ")
 		}
-		b.WriteString(string(k[0][0].(starlark.String)) + "=" + k[0][1].String())
+		b.WriteString(string(k[0][0].(starlark.String)) + "=" + argString(thread, k[0][1]))
 		for _, s := range k[1:] {
 			b.WriteString(", ")
-			b.WriteString(string(s[0].(starlark.String)) + "=" + s[1].String())
+			b.WriteString(string(s[0].(starlark.String)) + "=" + argString(thread, s[1]))
 		}
 	}
 
 	return b.String()
 }
 
+// argString formats an argument to a builtin. Statuses are named like in the script evaluated by thread.
+func argString(thread *starlark.Thread, v starlark.Value) string {
+	if s, ok := v.(Status); ok {
+		return statusName(thread, s)
+	}
+	return v.String()
+}
+
 func parameterAsInt64(v starlark.Value) (int64, error) {
 	if v == nil {
 		return 0, None
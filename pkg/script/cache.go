@@ -0,0 +1,97 @@
+package script
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"veidemann-scopeservice/pkg/telemetry"
+)
+
+// DefaultProgramCacheSize is the number of compiled scripts kept if InitializeProgramCache is never called.
+const DefaultProgramCacheSize = 100
+
+var programCache = newProgramCache(DefaultProgramCacheSize)
+
+// InitializeProgramCache replaces the compiled program cache with an empty cache holding at most size programs.
+// A size less than one disables caching.
+func InitializeProgramCache(size int) {
+	programCache = newProgramCache(size)
+}
+
+type cacheEntry struct {
+	key   string
+	value *Script
+}
+
+// lruProgramCache is a concurrency safe LRU cache of compiled programs.
+type lruProgramCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newProgramCache(capacity int) *lruProgramCache {
+	return &lruProgramCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// programKey returns the cache key for a script. Only sources of type string or []byte can be cached,
+// for other types ok is false.
+func programKey(name string, src interface{}) (key string, ok bool) {
+	h := sha256.New()
+	switch s := src.(type) {
+	case string:
+		h.Write([]byte(s))
+	case []byte:
+		h.Write(s)
+	default:
+		return "", false
+	}
+	return name + ":" + hex.EncodeToString(h.Sum(nil)), true
+}
+
+func (c *lruProgramCache) get(key string) (*Script, bool) {
+	if c.capacity < 1 {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		telemetry.ScriptCacheHitsTotal.Inc()
+		return e.Value.(*cacheEntry).value, true
+	}
+	telemetry.ScriptCacheMissesTotal.Inc()
+	return nil, false
+}
+
+func (c *lruProgramCache) add(key string, value *Script) {
+	if c.capacity < 1 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.ll.MoveToFront(e)
+		e.Value.(*cacheEntry).value = value
+		return
+	}
+	c.items[key] = c.ll.PushFront(&cacheEntry{key: key, value: value})
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*cacheEntry).key)
+		telemetry.ScriptCacheEvictionsTotal.Inc()
+	}
+}
+
+func (c *lruProgramCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
@@ -0,0 +1,55 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+)
+
+func Test_programCache(t *testing.T) {
+	defer InitializeProgramCache(DefaultProgramCacheSize)
+	InitializeProgramCache(2)
+
+	p1 := CompileScopeScript("a", "test(True).then(Include)")
+	if p1.err != nil {
+		t.Fatalf("CompileScopeScript() error = %v", p1.err)
+	}
+	if p := CompileScopeScript("a", "test(True).then(Include)"); p != p1 {
+		t.Errorf("CompileScopeScript() expected cached program for same name and source")
+	}
+	if p := CompileScopeScript("b", "test(True).then(Include)"); p == p1 {
+		t.Errorf("CompileScopeScript() expected new program for different name")
+	}
+	if p := CompileScopeScript("a", "test(False).then(Include)"); p == p1 {
+		t.Errorf("CompileScopeScript() expected new program for different source")
+	}
+	if l := programCache.len(); l != 2 {
+		t.Errorf("programCache.len() got = %v, want 2", l)
+	}
+	if p := CompileScopeScript("a", "test(True).then(Include)"); p == p1 {
+		t.Errorf("CompileScopeScript() expected least recently used program to be evicted")
+	}
+
+	e1 := CompileScopeScript("bad", "test(")
+	if e1.err == nil {
+		t.Fatalf("CompileScopeScript() expected error")
+	}
+	if p := CompileScopeScript("bad", "test("); p != e1 {
+		t.Errorf("CompileScopeScript() expected cached compile error")
+	}
+}
+
+func Test_programCacheDisabled(t *testing.T) {
+	defer InitializeProgramCache(DefaultProgramCacheSize)
+	InitializeProgramCache(0)
+
+	p1 := CompileScopeScript("a", "test(True).then(Include)")
+	if p := CompileScopeScript("a", "test(True).then(Include)"); p == p1 {
+		t.Errorf("CompileScopeScript() expected no caching when cache size is zero")
+	}
+	qUri := &frontier.QueuedUri{Uri: "http://foo.bar/"}
+	if got := RunScopeScript("a", "test(True).then(Include)", qUri, false); got.Evaluation != scopechecker.ScopeCheckResponse_INCLUDE {
+		t.Errorf("RunScopeScript().Evaluation got = %v, want %v", got.Evaluation, scopechecker.ScopeCheckResponse_INCLUDE)
+	}
+}
@@ -1,14 +1,31 @@
 package script
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/nlnwa/whatwg-url/canonicalizer"
 	"github.com/nlnwa/whatwg-url/url"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the built-in canonicalization profiles.
+const (
+	ScopeProfileName = "scope"
+	CrawlProfileName = "crawl"
 )
 
 var ScopeCanonicalizationProfile url.Parser
 var CrawlCanonicalizationProfile url.Parser
 
-func InitializeCanonicalizationProfiles(includeFragment bool) {
+// canonicalizationProfiles holds all named profiles, including the built-in ones.
+var canonicalizationProfiles = map[string]url.Parser{}
+
+// InitializeCanonicalizationProfiles creates the built-in scope and crawl profiles. Query and path parameters
+// matching stripParams are removed by both profiles. An error is returned if a regular expression in stripParams is
+// illegal, in which case the profiles are not changed.
+func InitializeCanonicalizationProfiles(includeFragment bool, stripParams ...string) error {
 	opts := []url.ParserOption{
 		url.WithCollapseConsecutiveSlashes(),
 		url.WithSkipEqualsForEmptySearchParamsValue(),
@@ -20,7 +37,10 @@ func InitializeCanonicalizationProfiles(includeFragment bool) {
 	if !includeFragment {
 		opts = append(opts, canonicalizer.WithRemoveFragment())
 	}
-	ScopeCanonicalizationProfile = canonicalizer.New(opts...)
+	scope, err := newProfile(canonicalizer.New(opts...), false, stripParams)
+	if err != nil {
+		return fmt.Errorf("illegal strip params: %w", err)
+	}
 
 	opts = []url.ParserOption{
 		url.WithCollapseConsecutiveSlashes(),
@@ -32,5 +52,162 @@ func InitializeCanonicalizationProfiles(includeFragment bool) {
 	if !includeFragment {
 		opts = append(opts, canonicalizer.WithRemoveFragment())
 	}
-	CrawlCanonicalizationProfile = canonicalizer.New(opts...)
+	crawl, err := newProfile(canonicalizer.New(opts...), false, stripParams)
+	if err != nil {
+		return fmt.Errorf("illegal strip params: %w", err)
+	}
+
+	ScopeCanonicalizationProfile = scope
+	CrawlCanonicalizationProfile = crawl
+	canonicalizationProfiles = map[string]url.Parser{
+		ScopeProfileName: ScopeCanonicalizationProfile,
+		CrawlProfileName: CrawlCanonicalizationProfile,
+	}
+	return nil
+}
+
+// CanonicalizationProfileConfig is the configuration of a named canonicalization profile. Options which are
+// not set are turned off.
+type CanonicalizationProfileConfig struct {
+	CollapseConsecutiveSlashes          bool     `yaml:"collapseConsecutiveSlashes"`
+	SkipEqualsForEmptySearchParamsValue bool     `yaml:"skipEqualsForEmptySearchParamsValue"`
+	RemoveUserInfo                      bool     `yaml:"removeUserInfo"`
+	RemovePort                          bool     `yaml:"removePort"`
+	RemoveFragment                      bool     `yaml:"removeFragment"`
+	RepeatedPercentDecoding             bool     `yaml:"repeatedPercentDecoding"`
+	LowercasePath                       bool     `yaml:"lowercasePath"`
+	SortQuery                           string   `yaml:"sortQuery"` // one of none, keys or parameter
+	DefaultScheme                       string   `yaml:"defaultScheme"`
+	StripParams                         []string `yaml:"stripParams"` // query and path parameters to remove, see paramPatterns
+}
+
+// LoadCanonicalizationProfiles reads named canonicalization profiles from a YAML or JSON file mapping profile
+// names to CanonicalizationProfileConfig. Profiles named scope or crawl replace the built-in profiles.
+//
+// Example:
+//
+//	archive:
+//	  collapseConsecutiveSlashes: true
+//	  removeFragment: true
+//	  sortQuery: none
+//	  defaultScheme: http
+//	  stripParams: [jsessionid, phpsessid, utm_*, fbclid, /^ga_[0-9]+$/]
+func LoadCanonicalizationProfiles(name string) error {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var configs map[string]CanonicalizationProfileConfig
+	if err := yaml.Unmarshal(b, &configs); err != nil {
+		return fmt.Errorf("failed to parse canonicalization profiles %s: %w", name, err)
+	}
+
+	profiles := make(map[string]url.Parser, len(canonicalizationProfiles)+len(configs))
+	for k, v := range canonicalizationProfiles {
+		profiles[k] = v
+	}
+	for k, c := range configs {
+		p, err := c.newProfile()
+		if err != nil {
+			return fmt.Errorf("illegal canonicalization profile '%s': %w", k, err)
+		}
+		profiles[k] = p
+	}
+	canonicalizationProfiles = profiles
+	ScopeCanonicalizationProfile = profiles[ScopeProfileName]
+	CrawlCanonicalizationProfile = profiles[CrawlProfileName]
+	return nil
+}
+
+// CanonicalizationProfile returns the named canonicalization profile.
+func CanonicalizationProfile(name string) (url.Parser, error) {
+	p, ok := canonicalizationProfiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown canonicalization profile '%s'", name)
+	}
+	return p, nil
+}
+
+func (c CanonicalizationProfileConfig) newProfile() (url.Parser, error) {
+	var opts []url.ParserOption
+	if c.CollapseConsecutiveSlashes {
+		opts = append(opts, url.WithCollapseConsecutiveSlashes())
+	}
+	if c.SkipEqualsForEmptySearchParamsValue {
+		opts = append(opts, url.WithSkipEqualsForEmptySearchParamsValue())
+	}
+	if c.RemoveUserInfo {
+		opts = append(opts, canonicalizer.WithRemoveUserInfo())
+	}
+	if c.RemovePort {
+		opts = append(opts, canonicalizer.WithRemovePort())
+	}
+	if c.RemoveFragment {
+		opts = append(opts, canonicalizer.WithRemoveFragment())
+	}
+	if c.RepeatedPercentDecoding {
+		opts = append(opts, canonicalizer.WithRepeatedPercentDecoding())
+	}
+	switch strings.ToLower(c.SortQuery) {
+	case "", "none":
+	case "keys":
+		opts = append(opts, canonicalizer.WithSortQuery(canonicalizer.SortKeys))
+	case "parameter":
+		opts = append(opts, canonicalizer.WithSortQuery(canonicalizer.SortParameter))
+	default:
+		return nil, fmt.Errorf("unknown sortQuery '%s', must be one of none, keys or parameter", c.SortQuery)
+	}
+	if c.DefaultScheme != "" {
+		opts = append(opts, canonicalizer.WithDefaultScheme(c.DefaultScheme))
+	}
+
+	return newProfile(canonicalizer.New(opts...), c.LowercasePath, c.StripParams)
+}
+
+// profile extends a whatwg-url canonicalizer with steps which are applied after canonicalization.
+type profile struct {
+	url.Parser
+	lowercasePath bool
+	stripParams   *paramPatterns
+}
+
+func (p *profile) Parse(rawUrl string) (*url.Url, error) {
+	u, err := p.Parser.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+	return p.canonicalize(u), nil
+}
+
+func (p *profile) ParseRef(rawUrl, ref string) (*url.Url, error) {
+	u, err := p.Parser.ParseRef(rawUrl, ref)
+	if err != nil {
+		return nil, err
+	}
+	return p.canonicalize(u), nil
+}
+
+func (p *profile) canonicalize(u *url.Url) *url.Url {
+	if p.stripParams != nil {
+		p.stripParams.strip(u)
+	}
+	if p.lowercasePath {
+		u.SetPathname(strings.ToLower(u.Pathname()))
+	}
+	return u
+}
+
+// newProfile returns the canonicalizer, extended with the steps which are enabled.
+func newProfile(canonicalizer url.Parser, lowercasePath bool, stripParams []string) (url.Parser, error) {
+	if !lowercasePath && len(stripParams) == 0 {
+		return canonicalizer, nil
+	}
+	p := &profile{Parser: canonicalizer, lowercasePath: lowercasePath}
+	if len(stripParams) > 0 {
+		var err error
+		if p.stripParams, err = newParamPatterns(stripParams); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
 }
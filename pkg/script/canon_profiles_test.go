@@ -0,0 +1,112 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+)
+
+const testProfiles = `
+unsorted:
+  removeFragment: true
+  defaultScheme: http
+  stripParams: [utm_*]
+lowercase:
+  lowercasePath: true
+  sortQuery: keys
+crawl:
+  removeFragment: true
+  sortQuery: parameter
+`
+
+func loadTestProfiles(t *testing.T, profiles string) error {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "profiles.yaml")
+	if err := os.WriteFile(name, []byte(profiles), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadCanonicalizationProfiles(name)
+}
+
+func TestLoadCanonicalizationProfiles(t *testing.T) {
+	defer InitializeCanonicalizationProfiles(false)
+	if err := loadTestProfiles(t, testProfiles); err != nil {
+		t.Fatalf("LoadCanonicalizationProfiles() error = %v", err)
+	}
+
+	tests := []struct {
+		profile string
+		uri     string
+		want    string
+	}{
+		{"unsorted", "foo.bar/Aa//bb?b=1&utm_source=x&a=2#frag", "http://foo.bar/Aa//bb?b=1&a=2"},
+		{"lowercase", "http://foo.bar/Aa/BB?b=1&a=2#frag", "http://foo.bar/aa/bb?a=2&b=1#frag"},
+		{"crawl", "http://foo.bar/aa?b=1&a=3&a=2#frag", "http://foo.bar/aa?a=2&a=3&b=1"},
+		{"scope", "http://foo.bar/aa//bb?b=1&a=2#frag", "http://foo.bar/aa/bb?a=2&b=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.profile, func(t *testing.T) {
+			p, err := CanonicalizationProfile(tt.profile)
+			if err != nil {
+				t.Fatalf("CanonicalizationProfile() error = %v", err)
+			}
+			u, err := p.Parse(tt.uri)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if u.String() != tt.want {
+				t.Errorf("Parse() got = %v, want %v", u.String(), tt.want)
+			}
+		})
+	}
+
+	if p, _ := CanonicalizationProfile(CrawlProfileName); p != CrawlCanonicalizationProfile {
+		t.Errorf("expected crawl profile from file to replace CrawlCanonicalizationProfile")
+	}
+	if _, err := CanonicalizationProfile("missing"); err == nil {
+		t.Errorf("CanonicalizationProfile() expected error for unknown profile")
+	}
+}
+
+func TestLoadCanonicalizationProfiles_illegal(t *testing.T) {
+	defer InitializeCanonicalizationProfiles(false)
+	if err := loadTestProfiles(t, "foo:\n  sortQuery: random\n"); err == nil {
+		t.Errorf("LoadCanonicalizationProfiles() expected error for illegal sortQuery")
+	}
+	if err := loadTestProfiles(t, "foo: [\n"); err == nil {
+		t.Errorf("LoadCanonicalizationProfiles() expected error for illegal file")
+	}
+}
+
+func Test_canonicalization(t *testing.T) {
+	defer InitializeCanonicalizationProfiles(false)
+	if err := loadTestProfiles(t, testProfiles); err != nil {
+		t.Fatalf("LoadCanonicalizationProfiles() error = %v", err)
+	}
+
+	qUri := &frontier.QueuedUri{Uri: "http://foo.bar/aa?b=1&a=2"}
+	tests := []struct {
+		name   string
+		script string
+		want   scopechecker.ScopeCheckResponse_Evaluation
+	}{
+		{"default", "isUrl('http://foo.bar/aa?a=2&b=1').then(Include)", scopechecker.ScopeCheckResponse_INCLUDE},
+		{"unsorted", "canonicalization('unsorted')\ntest(url().query == 'b=1&a=2').then(Include)", scopechecker.ScopeCheckResponse_INCLUDE},
+		{"sameProfileForIsUrl", "canonicalization('unsorted')\nisUrl('http://foo.bar/aa?a=2&b=1').then(Include)", scopechecker.ScopeCheckResponse_EXCLUDE},
+		{"unknown", "canonicalization('missing')\ntest(True).then(Include)", scopechecker.ScopeCheckResponse_EXCLUDE},
+		{"afterRemoveQuery", "removeQuery('a')\ncanonicalization('unsorted')\ntest(True).then(Include)", scopechecker.ScopeCheckResponse_EXCLUDE},
+		{"afterStripParams", "stripParams('a')\ncanonicalization('unsorted')\ntest(True).then(Include)", scopechecker.ScopeCheckResponse_EXCLUDE},
+		{"twice", "canonicalization('unsorted')\ncanonicalization('crawl')\ntest(url().query == 'a=2&b=1').then(Include)", scopechecker.ScopeCheckResponse_INCLUDE},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, qUri, false)
+			if got.Evaluation != tt.want {
+				t.Errorf("RunScopeScript().Evaluation got = %v, want %v, error: %v", got.Evaluation, tt.want, got.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package script
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+	"gopkg.in/yaml.v3"
+)
+
+// Custom status codes must be in one of these ranges. Codes outside the ranges are reserved for built-in statuses.
+var customStatusRanges = [][2]Status{
+	{-4999, -4010},
+	{-5999, -5010},
+}
+
+// statusesKey is the thread local holding the statuses declared in the headers of the script and its modules.
+const statusesKey = "statuses"
+
+// RegisterStatus adds a custom status which all scripts can use like the built-in statuses. Registering the same
+// name with the same code again is allowed. Statuses must be registered before scripts are evaluated, a script
+// can declare its own statuses in a header instead, see parseStatusHeader.
+func RegisterStatus(name string, code int32) error {
+	if err := registerStatus(name, Status(code)); err != nil {
+		return err
+	}
+	starlark.Universe[name] = Status(code)
+	return nil
+}
+
+// registerStatus validates a custom status and adds it to the known statuses, but not to the Starlark universe.
+func registerStatus(name string, status Status) error {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	registered, err := checkStatusLocked(name, status)
+	if err != nil || registered {
+		return err
+	}
+	statusNames[status] = name
+	statusValues[name] = status
+	return nil
+}
+
+// checkStatus validates a custom status without registering it.
+func checkStatus(name string, status Status) error {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	_, err := checkStatusLocked(name, status)
+	return err
+}
+
+// checkStatusLocked validates a custom status and reports whether it is already registered. The caller must hold
+// statusMu.
+func checkStatusLocked(name string, status Status) (bool, error) {
+	if !isIdentifier(name) {
+		return false, fmt.Errorf("illegal status name '%s'", name)
+	}
+	if !isCustomStatus(status) {
+		return false, fmt.Errorf("illegal code %d for status %s, custom codes must be in one of the ranges %s", status, name, customStatusRangesString())
+	}
+	if s, ok := statusValues[name]; ok {
+		if s == status {
+			return true, nil
+		}
+		return false, fmt.Errorf("status %s is already registered with code %d", name, s)
+	}
+	if n, ok := statusNames[status]; ok {
+		return false, fmt.Errorf("code %d for status %s is already used by status %s", status, name, n)
+	}
+	if _, ok := starlark.Universe[name]; ok {
+		return false, fmt.Errorf("status name %s is already used by a builtin", name)
+	}
+	return false, nil
+}
+
+func isCustomStatus(s Status) bool {
+	for _, r := range customStatusRanges {
+		if s >= r[0] && s <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func customStatusRangesString() string {
+	var r []string
+	for _, cr := range customStatusRanges {
+		r = append(r, fmt.Sprintf("[%d, %d]", cr[0], cr[1]))
+	}
+	return strings.Join(r, ", ")
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadStatusCodes registers the custom statuses in a YAML or JSON file mapping names to codes, e.g.
+//
+//	BlockedByRobotsPolicy: -5010
+//	OutOfCollectionScope: -5020
+func LoadStatusCodes(name string) error {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	var codes map[string]int32
+	if err := yaml.Unmarshal(b, &codes); err != nil {
+		return fmt.Errorf("failed to parse status codes %s: %w", name, err)
+	}
+	for k, v := range codes {
+		if err := RegisterStatus(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statusHeaderPattern matches a status declaration in a script header, e.g. '# status: OutOfCollectionScope = -5020'.
+var statusHeaderPattern = regexp.MustCompile(`^#\s*status:\s*(\S+)\s*=\s*(-?[0-9]+)\s*$`)
+
+// statusDecl is a status declared in a script header.
+type statusDecl struct {
+	name   string
+	status Status
+	pos    syntax.Position
+}
+
+// parseStatusHeader returns the custom statuses declared in the leading comments of a script as predeclared values
+// for the script. The statuses are only known to the script, they are not registered.
+func parseStatusHeader(name string, src interface{}) (starlark.StringDict, error) {
+	decls, err := scanStatusHeader(name, src)
+	if err != nil {
+		return nil, err
+	}
+	var predeclared starlark.StringDict
+	for _, d := range decls {
+		if err := checkHeaderStatus(predeclared, d); err != nil {
+			return nil, syntax.Error{Pos: d.pos, Msg: err.Error()}
+		}
+		if predeclared == nil {
+			predeclared = starlark.StringDict{}
+		}
+		predeclared[d.name] = d.status
+	}
+	return predeclared, nil
+}
+
+// checkHeaderStatus validates a status declared in a script header against the registered statuses and the
+// statuses declared before it in the same header.
+func checkHeaderStatus(declared starlark.StringDict, d statusDecl) error {
+	if err := checkStatus(d.name, d.status); err != nil {
+		return err
+	}
+	return checkStatusConflict(declared, d.name, d.status)
+}
+
+// checkStatusConflict returns an error if the name or the code of a status is used by another status in statuses.
+func checkStatusConflict(statuses starlark.StringDict, name string, status Status) error {
+	for n, v := range statuses {
+		if n == name && v != status {
+			return fmt.Errorf("status %s is already declared with code %d", name, v)
+		}
+		if n != name && v == status {
+			return fmt.Errorf("code %d for status %s is already used by status %s", status, name, n)
+		}
+	}
+	return nil
+}
+
+// mergeStatuses returns the statuses in dst and src. The maps are not modified.
+func mergeStatuses(dst, src starlark.StringDict) (starlark.StringDict, error) {
+	merged := starlark.StringDict{}
+	for n, v := range dst {
+		merged[n] = v
+	}
+	for n, v := range src {
+		if err := checkStatusConflict(merged, n, v.(Status)); err != nil {
+			return nil, err
+		}
+		merged[n] = v
+	}
+	return merged, nil
+}
+
+// lookupStatusName returns the name of a status declared in statuses, or the name of a built-in or registered status.
+func lookupStatusName(statuses starlark.StringDict, s Status) string {
+	for n, v := range statuses {
+		if v == s {
+			return n
+		}
+	}
+	return s.String()
+}
+
+// lookupStatus returns the status with the given name declared in statuses, or a built-in or registered status.
+func lookupStatus(statuses starlark.StringDict, name string) (Status, bool) {
+	if v, ok := statuses[name].(Status); ok {
+		return v, true
+	}
+	return StatusByName(name)
+}
+
+// statusName returns the name of a status for the script evaluated by thread.
+func statusName(thread *starlark.Thread, s Status) string {
+	statuses, _ := thread.Local(statusesKey).(starlark.StringDict)
+	return lookupStatusName(statuses, s)
+}
+
+// statusArg unpacks a status argument to a builtin. Unlike Status, a status can be given by the name of a status
+// declared in the header of the script evaluated by thread.
+type statusArg struct {
+	thread *starlark.Thread
+	status Status
+}
+
+func (a *statusArg) Unpack(v starlark.Value) error {
+	if name, ok := v.(starlark.String); ok {
+		statuses, _ := a.thread.Local(statusesKey).(starlark.StringDict)
+		if s, ok := lookupStatus(statuses, name.GoString()); ok {
+			a.status = s
+			return nil
+		}
+	}
+	return a.status.Unpack(v)
+}
+
+// scanStatusHeader returns the statuses declared in the leading comments of a script without validating them.
+func scanStatusHeader(name string, src interface{}) ([]statusDecl, error) {
+	var b []byte
+	switch s := src.(type) {
+	case string:
+		b = []byte(s)
+	case []byte:
+		b = s
+	default:
+		return nil, nil
+	}
+
+	var decls []statusDecl
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if !strings.HasPrefix(text, "#") {
+			break
+		}
+		m := statusHeaderPattern.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+		pos := syntax.MakePosition(&name, int32(line), 1)
+		code, err := strconv.ParseInt(m[2], 10, 32)
+		if err != nil {
+			return nil, syntax.Error{Pos: pos, Msg: err.Error()}
+		}
+		decls = append(decls, statusDecl{name: m[1], status: Status(code), pos: pos})
+	}
+	return decls, nil
+}
@@ -0,0 +1,156 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"go.starlark.net/starlark"
+)
+
+// unregisterStatus removes a custom status so tests can be run repeatedly.
+func unregisterStatus(name string) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	if s, ok := statusValues[name]; ok && isCustomStatus(s) {
+		delete(statusNames, s)
+		delete(statusValues, name)
+		delete(starlark.Universe, name)
+	}
+}
+
+func TestRegisterStatus(t *testing.T) {
+	defer unregisterStatus("BlockedByRobotsPolicy")
+
+	tests := []struct {
+		name    string
+		status  string
+		code    int32
+		wantErr string
+	}{
+		{"custom", "BlockedByRobotsPolicy", -5010, ""},
+		{"same again", "BlockedByRobotsPolicy", -5010, ""},
+		{"other code", "BlockedByRobotsPolicy", -5011, "already registered with code -5010"},
+		{"code in use", "OtherPolicy", -5010, "already used by status BlockedByRobotsPolicy"},
+		{"builtin code", "MyBlocked", -5001, "custom codes must be in one of the ranges [-4999, -4010], [-5999, -5010]"},
+		{"builtin name", "Blocked", -5030, "already registered with code -5001"},
+		{"builtin function", "isUrl", -5030, "already used by a builtin"},
+		{"illegal name", "Out of scope", -5030, "illegal status name"},
+		{"positive code", "Positive", 200, "illegal code 200"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterStatus(tt.status, tt.code)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("RegisterStatus() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("RegisterStatus() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if got := Status(-5010).String(); got != "BlockedByRobotsPolicy" {
+		t.Errorf("Status.String() got = %v, want BlockedByRobotsPolicy", got)
+	}
+	if s, ok := StatusByName("BlockedByRobotsPolicy"); !ok || s != -5010 {
+		t.Errorf("StatusByName() got = %v, %v", s, ok)
+	}
+	qUri := &frontier.QueuedUri{Uri: "http://www.example.com/"}
+	if got := RunScopeScript("custom", "test(True).then(BlockedByRobotsPolicy)", qUri, false); got.ExcludeReason != -5010 {
+		t.Errorf("RunScopeScript().ExcludeReason got = %v, want -5010", got.ExcludeReason)
+	}
+}
+
+func TestLoadStatusCodes(t *testing.T) {
+	defer unregisterStatus("OutOfCollectionScope")
+	defer unregisterStatus("BlockedByRobotsPolicy")
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "codes.yaml")
+	if err := os.WriteFile(file, []byte("BlockedByRobotsPolicy: -5010\nOutOfCollectionScope: -5020\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadStatusCodes(file); err != nil {
+		t.Fatalf("LoadStatusCodes() error = %v", err)
+	}
+	if got := Status(-5020).String(); got != "OutOfCollectionScope" {
+		t.Errorf("Status.String() got = %v, want OutOfCollectionScope", got)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("Bad: -4001\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadStatusCodes(bad); err == nil {
+		t.Errorf("LoadStatusCodes() expected error for built-in code")
+	}
+}
+
+func Test_parseStatusHeader(t *testing.T) {
+	qUri := &frontier.QueuedUri{Uri: "http://www.example.com/a/b/c"}
+	src := "# Collection scope\n" +
+		"# status: OutOfCollectionScope = -5020\n" +
+		"#status:TooDeep=-4020\n" +
+		"\n" +
+		"maxPathDepth(2).then(TooDeep)\n" +
+		"test(True).then(OutOfCollectionScope)\n" +
+		"# status: NotHeader = -5030\n"
+	got := RunScopeScript("header", src, qUri, true)
+	if got.ExcludeReason != -4020 {
+		t.Errorf("RunScopeScript().ExcludeReason got = %v, want -4020, error = %v", got.ExcludeReason, got.Error)
+	}
+	if !strings.Contains(got.Console, "match.then(TooDeep) status=TooDeep") {
+		t.Errorf("RunScopeScript().Console got = %q, want the name of the declared status", got.Console)
+	}
+
+	compiled := CompileScopeScript("header", src)
+	if name := compiled.StatusName(-5020); name != "OutOfCollectionScope" {
+		t.Errorf("Script.StatusName() got = %v, want OutOfCollectionScope", name)
+	}
+	if s, ok := compiled.StatusByName("TooDeep"); !ok || s != -4020 {
+		t.Errorf("Script.StatusByName() got = %v, %v", s, ok)
+	}
+	if _, ok := compiled.StatusByName("NotHeader"); ok {
+		t.Errorf("Script.StatusByName() expected status declared after the header to be ignored")
+	}
+	// Header statuses are not registered
+	if name := Status(-5020).String(); name != "" {
+		t.Errorf("Status.String() got = %v, want no name for a status declared in a header", name)
+	}
+	if _, ok := StatusByName("OutOfCollectionScope"); ok {
+		t.Errorf("StatusByName() expected status declared in a header to be unknown")
+	}
+	if name := CompileScopeScript("other", "test(True).then(Include)").StatusName(-5020); name != "" {
+		t.Errorf("Script.StatusName() got = %v, want no name in other scripts", name)
+	}
+
+	tests := []struct {
+		name      string
+		src       string
+		want      Status
+		wantError string
+	}{
+		{"same code in other script", "# status: Offsite = -5020\ntest(True).then(Offsite)", -5020, ""},
+		{"same name in other script", "# status: OutOfCollectionScope = -5021\ntest(True).then(OutOfCollectionScope)", -5021, ""},
+		{"set status by name", "# status: OutOfScope = -5022\nsetStatus('OutOfScope')", -5022, ""},
+		{"undeclared name", "test(True).then(TooDeep)", RuntimeException, "undefined: TooDeep"},
+		{"undeclared status by name", "setStatus('OutOfCollectionScope')", RuntimeException, "Illegal type \"OutOfCollectionScope\""},
+		{"builtin code", "# status: Conflict = -5001\ntest(True).then(Include)", RuntimeException, "conflict:1:1: illegal code -5001"},
+		{"name declared twice", "# status: Twice = -5020\n# status: Twice = -5021\n", RuntimeException, "conflict:2:1: status Twice is already declared with code -5020"},
+		{"code declared twice", "# status: One = -5020\n# status: Two = -5020\n", RuntimeException, "conflict:2:1: code -5020 for status Two is already used by status One"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript("conflict", tt.src, qUri, false)
+			if got.ExcludeReason != tt.want.AsInt32() {
+				t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v, error = %v", got.ExcludeReason, tt.want.AsInt32(), got.Error)
+			}
+			if tt.wantError != "" && (got.Error == nil || !strings.Contains(got.Error.Detail, tt.wantError)) {
+				t.Errorf("RunScopeScript().Error got = %v, want %v", got.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package script
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"go.starlark.net/starlark"
+)
+
+func init() {
+	starlark.Universe["isIpInRange"] = starlark.NewBuiltin("isIpInRange", isIpInRange)
+	starlark.Universe["isPrivateAddress"] = starlark.NewBuiltin("isPrivateAddress", isPrivateAddress)
+}
+
+// maxCachedIpRangeLists is the max number of range lists kept in the ip range cache. The cache is cleared when the
+// limit is reached.
+const maxCachedIpRangeLists = 1000
+
+// privateRanges are the ranges matched by isPrivateAddress which are not covered by the private, loopback or
+// link-local ranges.
+var privateRanges = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "this network" (RFC 1122), reaches the local host on some systems
+	netip.MustParsePrefix("100.64.0.0/10"), // shared address space for carrier-grade NAT (RFC 6598), includes the Alibaba Cloud metadata service
+}
+
+// urlIp returns the ip address of the url. A host which is an ip address is used before the resolved ip in the
+// queued uri. The second return value is false if the url has no known ip address.
+func urlIp(u *UrlValue) (netip.Addr, bool, error) {
+	host := strings.TrimSuffix(strings.TrimPrefix(u.parsedUri.Hostname(), "["), "]")
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return ip.Unmap(), true, nil
+	}
+	if u.qUri.Ip == "" {
+		return netip.Addr{}, false, nil
+	}
+	ip, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(u.qUri.Ip, "["), "]"))
+	if err != nil {
+		return netip.Addr{}, false, fmt.Errorf("illegal ip address '%s'", u.qUri.Ip)
+	}
+	return ip.Unmap(), true, nil
+}
+
+// parseIpRanges parses a whitespace separated list of CIDRs. A single address is a range with only that address.
+func parseIpRanges(cidrs string) ([]netip.Prefix, error) {
+	var ranges []netip.Prefix
+	for _, c := range strings.Fields(cidrs) {
+		if !strings.Contains(c, "/") {
+			ip, err := netip.ParseAddr(c)
+			if err != nil {
+				return nil, fmt.Errorf("illegal ip range '%s'", c)
+			}
+			ranges = append(ranges, netip.PrefixFrom(ip.Unmap(), ip.Unmap().BitLen()))
+			continue
+		}
+		p, err := netip.ParsePrefix(c)
+		if err != nil {
+			return nil, fmt.Errorf("illegal ip range '%s'", c)
+		}
+		if p.Addr().Is4In6() {
+			// A shorter prefix would include addresses which are not ipv4-mapped
+			if p.Bits() < 96 {
+				return nil, fmt.Errorf("illegal ip range '%s', an ipv4-mapped range must be at least /96", c)
+			}
+			p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+		}
+		ranges = append(ranges, p.Masked())
+	}
+	return ranges, nil
+}
+
+// ipRangeCache holds parsed range lists so that a list is only parsed once for a script.
+var ipRangeCache = struct {
+	sync.Mutex
+	m map[string][]netip.Prefix
+}{m: make(map[string][]netip.Prefix)}
+
+func cachedIpRanges(cidrs string) ([]netip.Prefix, error) {
+	ipRangeCache.Lock()
+	r, ok := ipRangeCache.m[cidrs]
+	ipRangeCache.Unlock()
+	if ok {
+		return r, nil
+	}
+
+	r, err := parseIpRanges(cidrs)
+	if err != nil {
+		return nil, err
+	}
+
+	ipRangeCache.Lock()
+	if len(ipRangeCache.m) >= maxCachedIpRangeLists {
+		ipRangeCache.m = make(map[string][]netip.Prefix)
+	}
+	ipRangeCache.m[cidrs] = r
+	ipRangeCache.Unlock()
+	return r, nil
+}
+
+func isIpInRange(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var cidrs string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "cidrs", &cidrs); err != nil {
+		return nil, err
+	}
+	ranges, err := cachedIpRanges(cidrs)
+	if err != nil {
+		return nil, err
+	}
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	ip, ok, err := urlIp(qUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	match := false
+	if ok {
+		for _, r := range ranges {
+			if r.Contains(ip) {
+				match = true
+				break
+			}
+		}
+	}
+
+	printDebugValues(thread, b, args, kwargs, debugValue{"ip", ipString(ip, ok)}, debugValue{"match", Match(match)})
+
+	return Match(match), nil
+}
+
+func isPrivateAddress(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	ip, ok, err := urlIp(qUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	match := ok && (ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified())
+	if ok && !match {
+		for _, r := range privateRanges {
+			if r.Contains(ip) {
+				match = true
+				break
+			}
+		}
+	}
+
+	printDebugValues(thread, b, args, kwargs, debugValue{"ip", ipString(ip, ok)}, debugValue{"match", Match(match)})
+
+	return Match(match), nil
+}
+
+func ipString(ip netip.Addr, ok bool) string {
+	if !ok {
+		return "None"
+	}
+	return ip.String()
+}
@@ -0,0 +1,86 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/commons/v1"
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+)
+
+func Test_ipMatchers(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		uri    string
+		ip     string
+		want   Status
+	}{
+		{"resolved ip in range", "isIpInRange('10.0.0.0/8 192.168.0.0/16').then(Blocked)", "http://www.example.com/", "192.168.1.10", Blocked},
+		{"resolved ip not in range", "isIpInRange('10.0.0.0/8').then(Blocked)", "http://www.example.com/", "11.0.0.1", Include},
+		{"single address", "isIpInRange('8.8.8.8').then(Blocked)", "http://www.example.com/", "8.8.8.8", Blocked},
+		{"ipv6 in range", "isIpInRange('2001:db8::/32').then(Blocked)", "http://www.example.com/", "2001:db8::1", Blocked},
+		{"literal ipv4 host", "isIpInRange('127.0.0.0/8').then(Blocked)", "http://0x7f.1/", "", Blocked},
+		{"literal ipv6 host", "isIpInRange('::1/128').then(Blocked)", "http://[::1]:8080/", "", Blocked},
+		{"ipv4 mapped ipv6", "isIpInRange('10.0.0.0/8').then(Blocked)", "http://[::ffff:10.0.0.1]/", "", Blocked},
+		{"ipv4 mapped range", "isIpInRange('::ffff:10.0.0.0/104').then(Blocked)", "http://www.example.com/", "10.0.0.1", Blocked},
+		{"ipv4 mapped range too short", "isIpInRange('::ffff:0.0.0.0/95').then(Blocked)", "http://www.example.com/", "10.0.0.1", RuntimeException},
+		{"literal host before resolved ip", "isIpInRange('10.0.0.0/8').then(Blocked)", "http://10.0.0.1/", "8.8.8.8", Blocked},
+		{"no ip", "isIpInRange('0.0.0.0/0').then(Blocked)", "http://www.example.com/", "", Include},
+		{"illegal range", "isIpInRange('10.0.0.0/33').then(Blocked)", "http://www.example.com/", "10.0.0.1", RuntimeException},
+		{"illegal ip", "isIpInRange('10.0.0.0/8').then(Blocked)", "http://www.example.com/", "10.0.0", RuntimeException},
+		{"private", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "172.16.5.4", Blocked},
+		{"private ipv6", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "fd00:ec2::254", Blocked},
+		{"loopback", "isPrivateAddress().then(Blocked)", "http://127.0.0.1/", "", Blocked},
+		{"link-local", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "fe80::1", Blocked},
+		{"metadata service", "isPrivateAddress().then(Blocked)", "http://169.254.169.254/latest/meta-data/", "", Blocked},
+		{"alibaba metadata service", "isPrivateAddress().then(Blocked)", "http://100.100.100.200/", "", Blocked},
+		{"unspecified", "isPrivateAddress().then(Blocked)", "http://0.0.0.0/", "", Blocked},
+		{"this network", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "0.1.2.3", Blocked},
+		{"shared address space", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "100.64.0.1", Blocked},
+		{"shared address space end", "isPrivateAddress().then(Blocked)", "http://100.127.255.254/", "", Blocked},
+		{"after shared address space", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "100.128.0.1", Include},
+		{"public", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "93.184.216.34", Include},
+		{"unknown", "isPrivateAddress().then(Blocked)", "http://www.example.com/", "", Include},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qUri := &frontier.QueuedUri{Uri: tt.uri, Ip: tt.ip}
+			got := RunScopeScript(tt.name, tt.script+"\ntest(True).then(Include)", qUri, false)
+			if got.ExcludeReason != tt.want.AsInt32() {
+				t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v, error: %v", got.ExcludeReason, tt.want.AsInt32(), got.Error)
+			}
+		})
+	}
+}
+
+func Test_isIpInRange(t *testing.T) {
+	tests := []testdata{
+		{name: "isIpInRange1",
+			script: "isIpInRange('192.168.0.0/16').then(Blocked)",
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/aa",
+				Ip:  "192.168.1.10",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "isIpInRange1:1:12 isIpInRange(\"192.168.0.0/16\") ip=192.168.1.10, match=True\n" +
+					"isIpInRange1:1:35 match.then(Blocked) status=Blocked\n",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,361 @@
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"veidemann-scopeservice/pkg/telemetry"
+
+	"github.com/rs/zerolog/log"
+	"go.starlark.net/starlark"
+	"golang.org/x/net/idna"
+)
+
+func init() {
+	starlark.Universe["inList"] = starlark.NewBuiltin("inList", inList)
+}
+
+// File extensions of the scope list kinds. A list is named by its file name without the extension.
+const (
+	hostListExt   = ".hosts"
+	domainListExt = ".domains"
+	prefixListExt = ".prefixes"
+)
+
+// scopeList is a list of hosts, domains or URL prefixes which values can be looked up in.
+type scopeList interface {
+	contains(value string) bool
+	size() int
+}
+
+// hostList matches hosts exactly.
+type hostList map[string]struct{}
+
+func (l hostList) contains(host string) bool {
+	_, ok := l[host]
+	return ok
+}
+
+func (l hostList) size() int {
+	return len(l)
+}
+
+// domainList matches a domain and all its subdomains, e.g. 'example.com' matches 'example.com' and 'www.example.com'.
+type domainList map[string]struct{}
+
+func (l domainList) contains(host string) bool {
+	for {
+		if _, ok := l[host]; ok {
+			return true
+		}
+		i := strings.IndexByte(host, '.')
+		if i < 0 {
+			return false
+		}
+		host = host[i+1:]
+	}
+}
+
+func (l domainList) size() int {
+	return len(l)
+}
+
+// prefixList matches values starting with any of the prefixes. Prefixes are indexed by length, so a lookup is
+// one map lookup for each distinct prefix length.
+type prefixList struct {
+	prefixes map[string]struct{}
+	lengths  []int
+}
+
+func (l *prefixList) contains(value string) bool {
+	for _, n := range l.lengths {
+		if n > len(value) {
+			return false
+		}
+		if _, ok := l.prefixes[value[:n]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *prefixList) size() int {
+	return len(l.prefixes)
+}
+
+// readScopeList reads a list file with one entry on each line. Empty lines and lines starting with '#' are skipped.
+// Hosts and domains are lower-cased and converted to punycode to compare equal to the hosts of canonicalized URLs,
+// and prefixes are canonicalized like the URLs they are compared to.
+func readScopeList(file string) (scopeList, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ext := filepath.Ext(file)
+	entries := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if ext == prefixListExt {
+			entry, err = normalizeListPrefix(entry)
+		} else {
+			if ext == domainListExt {
+				entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+			}
+			entry, err = normalizeListHost(entry)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", file, line, err)
+		}
+		entries[entry] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	switch ext {
+	case hostListExt:
+		return hostList(entries), nil
+	case domainListExt:
+		return domainList(entries), nil
+	default:
+		l := &prefixList{prefixes: entries}
+		seen := make(map[int]bool)
+		for p := range entries {
+			if !seen[len(p)] {
+				seen[len(p)] = true
+				l.lengths = append(l.lengths, len(p))
+			}
+		}
+		sort.Ints(l.lengths)
+		return l, nil
+	}
+}
+
+// listHostProfile converts hosts in lists to ASCII. Underscores are allowed since they are used in real host names.
+var listHostProfile = idna.New(idna.MapForLookup(), idna.StrictDomainName(false), idna.Transitional(false))
+
+func normalizeListHost(host string) (string, error) {
+	if strings.ContainsAny(host, " \t/:") {
+		return "", fmt.Errorf("illegal host '%s'", host)
+	}
+	h, err := listHostProfile.ToASCII(strings.TrimSuffix(host, "."))
+	if err != nil {
+		return "", fmt.Errorf("illegal host '%s': %w", host, err)
+	}
+	return h, nil
+}
+
+// normalizeListPrefix canonicalizes a prefix with the scope canonicalization profile, like newSurtPrefixes does.
+func normalizeListPrefix(prefix string) (string, error) {
+	u, err := ScopeCanonicalizationProfile.Parse(prefix)
+	if err != nil {
+		return "", fmt.Errorf("illegal prefix '%s': %w", prefix, err)
+	}
+	return u.String(), nil
+}
+
+func isScopeListFile(file string) bool {
+	switch filepath.Ext(file) {
+	case hostListExt, domainListExt, prefixListExt:
+		return true
+	}
+	return false
+}
+
+// loadedList is a scope list and the file it was loaded from.
+type loadedList struct {
+	file string
+	list scopeList
+}
+
+// listRegistry holds the scope lists in a directory and reloads them when they change.
+type listRegistry struct {
+	mu      sync.RWMutex
+	lists   map[string]*loadedList
+	failed  map[string]bool // lists which failed to load and have no previous version
+	watcher *dirWatcher
+}
+
+var lists *listRegistry
+
+// InitializeLists loads the host (.hosts), domain (.domains) and URL prefix (.prefixes) lists in dir and
+// watches the directory for changes. Scripts look up values in the lists with inList(). An empty dir disables lists.
+func InitializeLists(dir string) error {
+	if lists != nil {
+		lists.close()
+		lists = nil
+	}
+	if dir == "" {
+		return nil
+	}
+	r := &listRegistry{
+		lists:  make(map[string]*loadedList),
+		failed: make(map[string]bool),
+	}
+	w, err := watchDir(dir, isScopeListFile, r.reload)
+	if err != nil {
+		return fmt.Errorf("failed to load scope lists: %w", err)
+	}
+	r.watcher = w
+	lists = r
+	return nil
+}
+
+func lookupList(name string) (scopeList, bool) {
+	if lists == nil {
+		return nil, false
+	}
+	lists.mu.RLock()
+	defer lists.mu.RUnlock()
+	l, ok := lists.lists[name]
+	if !ok {
+		return nil, false
+	}
+	return l.list, true
+}
+
+// reload reads the list in file and replaces the loaded version. If the file is gone the list is removed. If the
+// list can not be read, the previous version is kept.
+func (r *listRegistry) reload(file string) {
+	name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+	start := time.Now()
+	l, err := readScopeList(file)
+	if os.IsNotExist(err) {
+		r.remove(name, file)
+		return
+	}
+	if err != nil {
+		telemetry.ScopeListReloadErrorsTotal.WithLabelValues(name).Inc()
+		log.Error().Err(err).Msgf("Failed to load scope list %s, keeping previous version", file)
+		r.markFailed(name)
+		return
+	}
+	telemetry.ScopeListLoadSeconds.WithLabelValues(name).Observe(time.Since(start).Seconds())
+
+	r.mu.Lock()
+	if prev, ok := r.lists[name]; ok && prev.file != file {
+		r.mu.Unlock()
+		telemetry.ScopeListReloadErrorsTotal.WithLabelValues(name).Inc()
+		log.Error().Msgf("Failed to load scope list %s, a list named %s is already loaded from %s", file, name, prev.file)
+		return
+	}
+	r.lists[name] = &loadedList{file: file, list: l}
+	delete(r.failed, name)
+	r.mu.Unlock()
+
+	telemetry.ScopeListEntries.WithLabelValues(name).Set(float64(l.size()))
+	telemetry.ScopeListLastReloadSeconds.WithLabelValues(name).SetToCurrentTime()
+	log.Info().Msgf("Loaded scope list %s with %d entries", name, l.size())
+}
+
+func (r *listRegistry) remove(name string, file string) {
+	r.mu.Lock()
+	prev, ok := r.lists[name]
+	if ok && prev.file == file {
+		delete(r.lists, name)
+	}
+	if !ok {
+		delete(r.failed, name)
+	}
+	r.mu.Unlock()
+
+	if ok && prev.file == file {
+		telemetry.ScopeListEntries.DeleteLabelValues(name)
+		telemetry.ScopeListLastReloadSeconds.DeleteLabelValues(name)
+		log.Info().Msgf("Removed scope list %s", name)
+	}
+}
+
+// markFailed marks a list as not loaded if there is no previous version of it.
+func (r *listRegistry) markFailed(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.lists[name]; !ok {
+		r.failed[name] = true
+	}
+}
+
+// ready returns true if every list in the directory has been loaded.
+func (r *listRegistry) ready() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.failed) == 0
+}
+
+func (r *listRegistry) close() {
+	r.watcher.close()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name := range r.lists {
+		telemetry.ScopeListEntries.DeleteLabelValues(name)
+		telemetry.ScopeListLastReloadSeconds.DeleteLabelValues(name)
+	}
+}
+
+// listValue returns the part of the url named by component which is looked up in a list.
+func listValue(u *UrlValue, component string) (string, error) {
+	switch component {
+	case "host":
+		return u.parsedUri.Hostname(), nil
+	case "href":
+		return u.parsedUri.String(), nil
+	case "seed", "seedHost":
+		return otherUrlValue(u, u.qUri.SeedUri, component == "seedHost")
+	case "referrer", "referrerHost":
+		return otherUrlValue(u, u.qUri.Referrer, component == "referrerHost")
+	}
+	return "", fmt.Errorf("unknown component '%s', must be one of host, href, seed, seedHost, referrer or referrerHost", component)
+}
+
+// otherUrlValue canonicalizes a url from the queued uri like the candidate url. An empty url returns an empty value.
+func otherUrlValue(u *UrlValue, rawUrl string, host bool) (string, error) {
+	if rawUrl == "" {
+		return "", nil
+	}
+	parsed, err := u.canonicalize(rawUrl)
+	if err != nil {
+		return "", IllegalUri.asError(fmt.Sprintf("Could not parse '%v'", rawUrl))
+	}
+	if host {
+		return parsed.Hostname(), nil
+	}
+	return parsed.String(), nil
+}
+
+func inList(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var component = "host"
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "component?", &component); err != nil {
+		return nil, err
+	}
+	l, ok := lookupList(name)
+	if !ok {
+		return nil, fmt.Errorf("no scope list named '%v'", name)
+	}
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	value, err := listValue(qUrl, component)
+	if err != nil {
+		return nil, err
+	}
+	match := value != "" && l.contains(value)
+
+	printDebugValues(thread, b, args, kwargs, debugValue{component, value}, debugValue{"match", Match(match)})
+
+	return Match(match), nil
+}
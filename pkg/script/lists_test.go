@@ -0,0 +1,176 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"veidemann-scopeservice/pkg/telemetry"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_readScopeList(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		match   []string
+		noMatch []string
+		wantErr bool
+	}{
+		{"hosts", "a.hosts", "# comment\nExample.com\n\nwww.example.org.\nbücher.example\n",
+			[]string{"example.com", "www.example.org", "xn--bcher-kva.example"},
+			[]string{"www.example.com", "example.org", "com"}, false},
+		{"domains", "a.domains", "example.com\n*.example.org\n.no\n",
+			[]string{"example.com", "www.example.com", "a.b.example.org", "example.org", "nb.no"},
+			[]string{"example.net", "badexample.com", "no.com"}, false},
+		{"prefixes", "a.prefixes", "http://example.com/a/\nhttp://example.org/\nhttps://example.org/abc\n",
+			[]string{"http://example.com/a/b", "http://example.org/", "https://example.org/abcd"},
+			[]string{"http://example.com/b/a/", "http://example.org", "https://example.org/ab"}, false},
+		{"canonicalized prefixes", "a.prefixes", "HTTP://Example.COM:80/a/\nhttp://bücher.example\nhttp://example.net/a b\n",
+			[]string{"http://example.com/a/b", "http://xn--bcher-kva.example/", "http://example.net/a%20b"},
+			[]string{"HTTP://Example.COM:80/a/", "http://bücher.example"}, false},
+		{"illegal prefix", "a.prefixes", "http://example.com/\nhttp://exa mple.com/\n", nil, nil, true},
+		{"illegal host", "a.hosts", "example.com\nexam ple.com\n", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(file, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			l, err := readScopeList(file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readScopeList() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, v := range tt.match {
+				if !l.contains(v) {
+					t.Errorf("contains(%q) = false, want true", v)
+				}
+			}
+			for _, v := range tt.noMatch {
+				if l.contains(v) {
+					t.Errorf("contains(%q) = true, want false", v)
+				}
+			}
+		})
+	}
+}
+
+func Test_inList(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"blocked.hosts":    "blocked.example.com\n",
+		"norway.domains":   "no\n",
+		"archive.prefixes": "http://example.com/archive/\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() { _ = InitializeLists("") }()
+	if err := InitializeLists(dir); err != nil {
+		t.Fatalf("InitializeLists() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		script string
+		qUri   *frontier.QueuedUri
+		want   Status
+		detail string
+	}{
+		{"host", "inList('blocked').then(Blocked)",
+			&frontier.QueuedUri{Uri: "http://blocked.example.com/a"}, Blocked, ""},
+		{"host no match", "inList('blocked').then(Blocked)",
+			&frontier.QueuedUri{Uri: "http://www.blocked.example.com/a"}, Include, ""},
+		{"domain", "inList('norway').otherwise(Blocked)",
+			&frontier.QueuedUri{Uri: "http://www.nb.no/a"}, Include, ""},
+		{"domain no match", "inList('norway').otherwise(Blocked)",
+			&frontier.QueuedUri{Uri: "http://www.nb.se/a"}, Blocked, ""},
+		{"seed host", "inList('norway', component='seedHost').otherwise(Blocked)",
+			&frontier.QueuedUri{Uri: "http://www.nb.se/a", SeedUri: "http://www.nb.no/"}, Include, ""},
+		{"href", "inList('archive', component='href').then(TooManyHops)",
+			&frontier.QueuedUri{Uri: "http://EXAMPLE.com/archive/2020"}, TooManyHops, ""},
+		{"referrer", "inList('archive', component='referrer').then(TooManyHops)",
+			&frontier.QueuedUri{Uri: "http://example.com/a", Referrer: "http://example.com/archive/"}, TooManyHops, ""},
+		{"empty referrer", "inList('archive', component='referrer').then(TooManyHops)",
+			&frontier.QueuedUri{Uri: "http://example.com/a"}, Include, ""},
+		{"unknown list", "inList('missing').then(Blocked)",
+			&frontier.QueuedUri{Uri: "http://example.com/a"}, RuntimeException, "no scope list named 'missing'"},
+		{"unknown component", "inList('blocked', component='path').then(Blocked)",
+			&frontier.QueuedUri{Uri: "http://example.com/a"}, RuntimeException, "unknown component 'path'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script+"\ntest(True).then(Include)", tt.qUri, false)
+			if got.ExcludeReason != tt.want.AsInt32() {
+				t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v, error: %v", got.ExcludeReason, tt.want.AsInt32(), got.Error)
+			}
+			if tt.detail != "" && (got.Error == nil || !strings.Contains(got.Error.Detail, tt.detail)) {
+				t.Errorf("RunScopeScript().Error got = %v, want detail containing %q", got.Error, tt.detail)
+			}
+		})
+	}
+}
+
+func Test_listRegistry(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "blocked.hosts")
+	writeFile := func(content string) {
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// eventually waits for the list to have the wanted number of entries, -1 means the list is removed
+	eventually := func(want int) {
+		t.Helper()
+		var got int
+		for i := 0; i < 100; i++ {
+			got = -1
+			if l, ok := lookupList("blocked"); ok {
+				got = l.size()
+			}
+			if got == want {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Errorf("list size got = %v, want %v", got, want)
+	}
+
+	writeFile("a.example.com\n")
+	defer func() { _ = InitializeLists("") }()
+	if err := InitializeLists(dir); err != nil {
+		t.Fatalf("InitializeLists() error = %v", err)
+	}
+	eventually(1)
+	if got := testutil.ToFloat64(telemetry.ScopeListEntries.WithLabelValues("blocked")); got != 1 {
+		t.Errorf("ScopeListEntries got = %v, want 1", got)
+	}
+
+	writeFile("a.example.com\nb.example.com\n")
+	eventually(2)
+
+	errors := testutil.ToFloat64(telemetry.ScopeListReloadErrorsTotal.WithLabelValues("blocked"))
+	writeFile("a.example.com\nb.exa mple.com\nc.example.com\n")
+	for i := 0; i < 100 && testutil.ToFloat64(telemetry.ScopeListReloadErrorsTotal.WithLabelValues("blocked")) == errors; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	eventually(2)
+
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	eventually(-1)
+	if got := testutil.CollectAndCount(telemetry.ScopeListEntries); got != 0 {
+		t.Errorf("ScopeListEntries got %v series, want 0", got)
+	}
+}
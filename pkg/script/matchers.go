@@ -3,6 +3,7 @@ package script
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"go.starlark.net/starlark"
@@ -12,9 +13,15 @@ func init() {
 	starlark.Universe["test"] = starlark.NewBuiltin("test", test)
 	starlark.Universe["isScheme"] = starlark.NewBuiltin("isScheme", isScheme)
 	starlark.Universe["isSameHost"] = starlark.NewBuiltin("isSameHost", isSameHost)
+	starlark.Universe["isSameRegisteredDomain"] = starlark.NewBuiltin("isSameRegisteredDomain", isSameRegisteredDomain)
 	starlark.Universe["maxHopsFromSeed"] = starlark.NewBuiltin("maxHopsFromSeed", maxHopsFromSeed)
+	starlark.Universe["maxTransitiveHops"] = starlark.NewBuiltin("maxTransitiveHops", maxTransitiveHops)
+	starlark.Universe["discoveryPathMatches"] = starlark.NewBuiltin("discoveryPathMatches", discoveryPathMatches)
 	starlark.Universe["isUrl"] = starlark.NewBuiltin("isUrl", isUrl)
 	starlark.Universe["isReferrer"] = starlark.NewBuiltin("isReferrer", isReferrer)
+	starlark.Universe["matchesRegex"] = starlark.NewBuiltin("matchesRegex", matchesRegex)
+	starlark.Universe["matchesGlob"] = starlark.NewBuiltin("matchesGlob", matchesGlob)
+	starlark.Universe["isSurtPrefix"] = starlark.NewBuiltin("isSurtPrefix", isSurtPrefix)
 }
 
 func test(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -23,7 +30,7 @@ func test(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwa
 		return nil, err
 	}
 	match := Match(parameterAsBool(m))
-	printDebug(thread, b, args, kwargs, fmt.Sprintf("match=%v", match))
+	printDebugValues(thread, b, args, kwargs, debugValue{"match", match})
 	return match, nil
 }
 
@@ -46,7 +53,7 @@ func isScheme(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple,
 		}
 	}
 
-	printDebugf(thread, b, args, kwargs, "scheme=%v, wantScheme=%v, match=%v", s, scheme, match)
+	printDebugValues(thread, b, args, kwargs, debugValue{"scheme", s}, debugValue{"wantScheme", scheme}, debugValue{"match", match})
 
 	return match, nil
 }
@@ -70,7 +77,7 @@ func isReferrer(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tupl
 		}
 	}
 
-	printDebugf(thread, b, args, kwargs, "referrer=%v, wantReferrer=%v, match=%v", s, referrer, match)
+	printDebugValues(thread, b, args, kwargs, debugValue{"referrer", s}, debugValue{"wantReferrer", referrer}, debugValue{"match", match})
 
 	return match, nil
 }
@@ -83,18 +90,55 @@ func isSameHost(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tupl
 	}
 
 	match := false
-	qUrl := thread.Local(urlKey).(*UrlValue)
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
 	host := qUrl.parsedUri.Hostname()
 
 	seeds := append(strings.Fields(altSeeds), qUrl.qUri.SeedUri)
 	for _, s := range seeds {
-		if seed, err := ScopeCanonicalizationProfile.Parse(s); err == nil {
+		if seed, err := qUrl.canonicalize(s); err == nil {
 			altSeeds = seed.Hostname()
 			match = host == altSeeds
 			if !match && parameterAsBool(includeSubdomains) {
 				match = strings.HasSuffix(host, "."+altSeeds)
 			}
-			printDebugf(thread, b, args, kwargs, "host=%v, seedHost=%v, match=%v", host, altSeeds, match)
+			printDebugValuesf(thread, b, args, kwargs, "host=%v, seedHost=%v, match=%v",
+				debugValue{"host", host}, debugValue{"seedHost", altSeeds}, debugValue{"match", match})
+			if match {
+				break
+			}
+		} else {
+			printDebugf(thread, b, args, kwargs, "Could not parse seed '%v'", s)
+			return nil, IllegalUri.asError(fmt.Sprintf("Could not parse seed '%v'", s))
+		}
+	}
+
+	return Match(match), nil
+}
+
+func isSameRegisteredDomain(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var altSeeds string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "altSeeds?", &altSeeds); err != nil {
+		return nil, err
+	}
+
+	match := false
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	host := qUrl.parsedUri.Hostname()
+	domain := registeredDomain(host)
+
+	seeds := append(strings.Fields(altSeeds), qUrl.qUri.SeedUri)
+	for _, s := range seeds {
+		if seed, err := qUrl.canonicalize(s); err == nil {
+			seedDomain := registeredDomain(seed.Hostname())
+			match = domain == seedDomain
+			printDebugValues(thread, b, args, kwargs, debugValue{"host", host}, debugValue{"domain", domain},
+				debugValue{"seedHost", seed.Hostname()}, debugValue{"seedDomain", seedDomain}, debugValue{"match", match})
 			if match {
 				break
 			}
@@ -113,7 +157,10 @@ func maxHopsFromSeed(thread *starlark.Thread, b *starlark.Builtin, args starlark
 	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "hops", &maxHops, "includeRedirects?", &includeRedirects); err != nil {
 		return nil, err
 	}
-	qUrl := thread.Local(urlKey).(*UrlValue)
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
 	discoveryPath := qUrl.qUri.GetDiscoveryPath()
 	if !parameterAsBool(includeRedirects) {
 		discoveryPath = strings.ReplaceAll(discoveryPath, "R", "")
@@ -128,7 +175,58 @@ func maxHopsFromSeed(thread *starlark.Thread, b *starlark.Builtin, args starlark
 			return nil, err
 		}
 	}
-	printDebugf(thread, b, args, kwargs, "discoveryPath=%v, hops=%v, match=%v", discoveryPath, len(discoveryPath), match)
+	printDebugValuesf(thread, b, args, kwargs, "discoveryPath=%v, hops=%v, match=%v",
+		debugValue{"discoveryPath", discoveryPath}, debugValue{"hops", len(discoveryPath)}, debugValue{"match", match})
+	return Match(match), nil
+}
+
+// maxTransitiveHops matches if the trailing run of non link hops (E, X, P, R, ...) in the discovery path is longer
+// than hops. Redirects are part of the run, but are only counted if countRedirects is True.
+func maxTransitiveHops(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var maxHops starlark.Value
+	var countRedirects starlark.Value
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "hops", &maxHops, "countRedirects?", &countRedirects); err != nil {
+		return nil, err
+	}
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	discoveryPath := qUrl.qUri.GetDiscoveryPath()
+	transitivePath := discoveryPath[strings.LastIndex(discoveryPath, "L")+1:]
+	if !parameterAsBool(countRedirects) {
+		transitivePath = strings.ReplaceAll(transitivePath, "R", "")
+	}
+
+	var match bool
+
+	if h, err := parameterAsInt64(maxHops); err == nil {
+		match = len(transitivePath) > int(h)
+	} else {
+		if errors.Is(err, None) {
+			return nil, err
+		}
+	}
+	printDebugValues(thread, b, args, kwargs, debugValue{"discoveryPath", discoveryPath}, debugValue{"hops", len(transitivePath)}, debugValue{"match", Match(match)})
+	return Match(match), nil
+}
+
+func discoveryPathMatches(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "pattern", &pattern); err != nil {
+		return nil, err
+	}
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	discoveryPath := qUrl.qUri.GetDiscoveryPath()
+	match := re.MatchString(discoveryPath)
+	printDebugValues(thread, b, args, kwargs, debugValue{"discoveryPath", discoveryPath}, debugValue{"match", Match(match)})
 	return Match(match), nil
 }
 
@@ -137,11 +235,14 @@ func isUrl(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kw
 	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &u); err != nil {
 		return nil, err
 	}
-	qUrl := thread.Local(urlKey).(*UrlValue)
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
 
 	match := False
 	for _, ux := range strings.Fields(u) {
-		canon, err := ScopeCanonicalizationProfile.Parse(ux)
+		canon, err := qUrl.canonicalize(ux)
 		if err != nil {
 			return nil, err
 		}
@@ -152,7 +253,72 @@ func isUrl(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kw
 		}
 	}
 
-	printDebugf(thread, b, args, kwargs, "test='%v', url=%v, match=%v", u, qUrl.String(), match)
+	printDebugValuesf(thread, b, args, kwargs, "test='%v', url=%v, match=%v",
+		debugValue{"test", u}, debugValue{"url", qUrl.String()}, debugValue{"match", match})
 
 	return match, nil
 }
+
+func matchesRegex(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern string
+	var component = "href"
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "pattern", &pattern, "component?", &component); err != nil {
+		return nil, err
+	}
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return matchPattern(thread, b, args, kwargs, re, component)
+}
+
+func matchesGlob(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var pattern string
+	var component = "href"
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "pattern", &pattern, "component?", &component); err != nil {
+		return nil, err
+	}
+	re, err := compileGlob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return matchPattern(thread, b, args, kwargs, re, component)
+}
+
+func matchPattern(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, re *regexp.Regexp, component string) (starlark.Value, error) {
+	qUrl, ok := thread.Local(urlKey).(*UrlValue)
+	if !ok {
+		return nil, fmt.Errorf("url not set")
+	}
+	component = strings.ToLower(component)
+	value, err := urlComponent(qUrl, component)
+	if err != nil {
+		return nil, err
+	}
+
+	match := Match(re.MatchString(value))
+	printDebugValues(thread, b, args, kwargs, debugValue{component, value}, debugValue{"match", match})
+
+	return match, nil
+}
+
+func isSurtPrefix(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var prefixes string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "prefixes", &prefixes); err != nil {
+		return nil, err
+	}
+	qUrl, ok := thread.Local(urlKey).(*UrlValue)
+	if !ok {
+		return nil, fmt.Errorf("url not set")
+	}
+	trie, err := cachedSurtPrefixes(prefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	surt := qUrl.Surt()
+	prefix, match := trie.match(surtForComparison(surt))
+	printDebugValues(thread, b, args, kwargs, debugValue{"surt", surt}, debugValue{"prefix", prefix}, debugValue{"match", Match(match)})
+
+	return Match(match), nil
+}
@@ -133,6 +133,26 @@ func Test_isSameHost(t *testing.T) {
 				},
 				Console: "",
 			}},
+		{name: "isSameHostDebug",
+			script: "isSameHost().then(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri:     "http://foo.bar/aa",
+				SeedUri: "http://foo.bar",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "isSameHostDebug:1:11 isSameHost() host=foo.bar, seedHost=foo.bar, match=true\n" +
+					"isSameHostDebug:1:18 match.then(Include) status=Include\n",
+			}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -451,6 +471,94 @@ func Test_isUrl(t *testing.T) {
 				},
 				Console: "",
 			}},
+		{name: "isUrlDebug",
+			script: "isUrl('http://foo.bar/aa#bb').then(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/aa",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "isUrlDebug:1:6 isUrl(\"http://foo.bar/aa#bb\") test='http://foo.bar/aa#bb', url=http://foo.bar/aa, match=True\n" +
+					"isUrlDebug:1:35 match.then(Include) status=Include\n",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
+
+func Test_isSameRegisteredDomain(t *testing.T) {
+	tests := []testdata{
+		{name: "isSameRegisteredDomain1",
+			script: "isSameRegisteredDomain().then(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri:     "http://tv.nrk.no/aa",
+				SeedUri: "http://www.nrk.no/",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://tv.nrk.no/aa",
+					Scheme: "http",
+					Host:   "tv.nrk.no",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "isSameRegisteredDomain1:1:23 isSameRegisteredDomain() host=tv.nrk.no, domain=nrk.no, seedHost=www.nrk.no, seedDomain=nrk.no, match=True\n" +
+					"isSameRegisteredDomain1:1:30 match.then(Include) status=Include\n",
+			}},
+		{name: "isSameRegisteredDomain2",
+			script: "isSameRegisteredDomain().otherwise(Blocked)",
+			qUri: &frontier.QueuedUri{
+				Uri:     "http://bbc.co.uk/aa",
+				SeedUri: "http://www.nhs.co.uk/",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://bbc.co.uk/aa",
+					Scheme: "http",
+					Host:   "bbc.co.uk",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "",
+			}},
+		{name: "isSameRegisteredDomain3",
+			script: "isSameRegisteredDomain(altSeeds='www.bbc.co.uk').then(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri:     "http://news.bbc.co.uk/aa",
+				SeedUri: "http://www.nhs.co.uk/",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://news.bbc.co.uk/aa",
+					Scheme: "http",
+					Host:   "news.bbc.co.uk",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "",
+			}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -594,6 +702,451 @@ func Test_maxHopsFromSeed(t *testing.T) {
 				},
 				Console: "",
 			}},
+		{name: "maxHopsFromSeedDebug",
+			script: "maxHopsFromSeed(2).then(TooManyHops)",
+			qUri: &frontier.QueuedUri{
+				Uri:           "http://foo.bar/aa",
+				DiscoveryPath: "RLERLR",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: TooManyHops.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "maxHopsFromSeedDebug:1:16 maxHopsFromSeed(2) discoveryPath=LEL, hops=3, match=true\n" +
+					"maxHopsFromSeedDebug:1:24 match.then(TooManyHops) status=TooManyHops\n",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
+
+func Test_matchesRegex(t *testing.T) {
+	tests := []testdata{
+		{name: "matchesRegex1",
+			script: `matchesRegex('^/calendar/.*', component='path').then(Blocked)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+		{name: "matchesRegex2",
+			script: `matchesRegex('date=\\d{4}-\\d{2}-\\d{2}', 'query').then(Blocked).otherwise(Include)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+		{name: "matchesRegex3",
+			script: `matchesRegex('^https://').then(Blocked).otherwise(Include)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+		{name: "matchesRegex4",
+			script: `matchesRegex('foo\\.bar$', component='HOST').then(Include)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "matchesRegex4:1:13 matchesRegex(\"foo\\\\.bar$\", component=\"HOST\") host=foo.bar, match=True\nmatchesRegex4:1:50 match.then(Include) status=Include\n",
+			}},
+		{name: "matchesRegex5",
+			script: `matchesRegex('foo', component='fragment').then(Include)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: RuntimeException.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Error: &commons.Error{
+					Code: RuntimeException.AsInt32(),
+					Msg:  "error executing scope script",
+					Detail: `Traceback (most recent call last):
+  matchesRegex5:1:13: in <toplevel>
+Error in matchesRegex: unknown url component 'fragment', must be one of href, host, path or query`,
+				},
+				Console: "",
+			}},
+		{name: "matchesRegex6",
+			script: `matchesRegex('^/calendar/', component='path').then(Blocked).otherwise(Include)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/Calendar/2020",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/Calendar/2020",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/Calendar/2020",
+				},
+				Console: "",
+			}},
+		{name: "matchesRegex7",
+			script: `matchesRegex('(?i)^/calendar/', component='path').then(Blocked).otherwise(Include)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/Calendar/2020",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/Calendar/2020",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/Calendar/2020",
+				},
+				Console: "",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
+
+func Test_matchesGlob(t *testing.T) {
+	tests := []testdata{
+		{name: "matchesGlob1",
+			script: `matchesGlob('/calendar/*', component='path').then(Blocked).otherwise(Include)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+		{name: "matchesGlob2",
+			script: `matchesGlob('/calendar/**', component='path').then(Blocked)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+		{name: "matchesGlob3",
+			script: `matchesGlob('/calendar/20[0-9][0-9]/??', component='path').then(Blocked)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+		{name: "matchesGlob4",
+			script: `matchesGlob('http://*.bar/**').then(Blocked)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+		{name: "matchesGlob5",
+			script: `matchesGlob('*date=*', 'query').then(Blocked)`,
+			qUri: &frontier.QueuedUri{
+				Uri: "http://foo.bar/calendar/2020/01?id=1&date=2020-01-01",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/calendar/2020/01?date=2020-01-01&id=1",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/calendar/2020/01",
+					Query:  "date=2020-01-01&id=1",
+				},
+				Console: "",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
+
+func Test_isSurtPrefix(t *testing.T) {
+	tests := []testdata{
+		{name: "isSurtPrefix1",
+			script: "isSurtPrefix(param('surtPrefixes')).then(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri: "http://www.nb.no/aa",
+				Annotation: []*config.Annotation{
+					{Key: "surtPrefixes", Value: "http://(no,vg,\nhttp://(no,nb,"},
+				},
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://www.nb.no/aa",
+					Scheme: "http",
+					Host:   "www.nb.no",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "isSurtPrefix1:1:13 isSurtPrefix(\"http://(no,vg,\\nhttp://(no,nb,\") surt=http://(no,nb,www,)/aa, prefix=http://(no,nb,, match=True\n" +
+					"isSurtPrefix1:1:41 match.then(Include) status=Include\n",
+			}},
+		{name: "isSurtPrefix2",
+			script: "isSurtPrefix('http://(no,vg, http://www.nb.no/bb/').otherwise(Blocked)",
+			qUri: &frontier.QueuedUri{
+				Uri: "http://www.nb.no/aa",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: Blocked.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://www.nb.no/aa",
+					Scheme: "http",
+					Host:   "www.nb.no",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
+
+func Test_maxTransitiveHops(t *testing.T) {
+	tests := []testdata{
+		{name: "maxTransitiveHops1",
+			script: "maxTransitiveHops(2).then(TooManyTransitiveHops).otherwise(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri:           "http://foo.bar/aa",
+				DiscoveryPath: "LLERE",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "",
+			}},
+		{name: "maxTransitiveHops2",
+			script: "maxTransitiveHops(2, countRedirects=True).then(TooManyTransitiveHops)",
+			qUri: &frontier.QueuedUri{
+				Uri:           "http://foo.bar/aa",
+				DiscoveryPath: "LLERE",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: TooManyTransitiveHops.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "maxTransitiveHops2:1:18 maxTransitiveHops(2, countRedirects=True) discoveryPath=LLERE, hops=3, match=True\n" +
+					"maxTransitiveHops2:1:47 match.then(TooManyTransitiveHops) status=TooManyTransitiveHops\n",
+			}},
+		{name: "maxTransitiveHops3",
+			script: "maxTransitiveHops(1).then(TooManyTransitiveHops).otherwise(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri:           "http://foo.bar/aa",
+				DiscoveryPath: "EEL",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
+
+func Test_discoveryPathMatches(t *testing.T) {
+	tests := []testdata{
+		{name: "discoveryPathMatches1",
+			script: "discoveryPathMatches('L[EX]{2,}$').then(TooManyTransitiveHops)",
+			qUri: &frontier.QueuedUri{
+				Uri:           "http://foo.bar/aa",
+				DiscoveryPath: "RLEX",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: TooManyTransitiveHops.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "discoveryPathMatches1:1:21 discoveryPathMatches(\"L[EX]{2,}$\") discoveryPath=RLEX, match=True\n" +
+					"discoveryPathMatches1:1:40 match.then(TooManyTransitiveHops) status=TooManyTransitiveHops\n",
+			}},
+		{name: "discoveryPathMatches2",
+			script: "discoveryPathMatches('^L').otherwise(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri:           "http://foo.bar/aa",
+				DiscoveryPath: "RLEX",
+			},
+			debug: false,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://foo.bar/aa",
+					Scheme: "http",
+					Host:   "foo.bar",
+					Port:   80,
+					Path:   "/aa",
+				},
+				Console: "",
+			}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,221 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+const (
+	loadingKey     = "loading"
+	contextKey     = "context"
+	moduleFilesKey = "moduleFiles"
+)
+
+// ModuleError is returned when a module loaded by a scope script could not be loaded.
+type ModuleError struct {
+	Module string
+	Err    error
+}
+
+func (e *ModuleError) Error() string {
+	return fmt.Sprintf("module %v: %v", e.Module, e.Err)
+}
+
+func (e *ModuleError) Unwrap() error {
+	return e.Err
+}
+
+var errModuleNotFound = errors.New("module not found")
+
+// moduleLoader resolves modules for the load statement. Modules registered with RegisterModule take precedence
+// over modules read from dir. Loaded modules are frozen and cached until a module is registered, or until a file
+// read for the module changes on disk.
+type moduleLoader struct {
+	mu         sync.Mutex
+	dir        string
+	registered map[string]string
+	cache      map[string]*loadedModule
+}
+
+// loadedModule is a module in the cache.
+type loadedModule struct {
+	globals starlark.StringDict
+	// files are the files read from disk for the module and the modules it loads, with their modification times
+	files map[string]time.Time
+	// statuses are the statuses declared in the headers of the module and the modules it loads
+	statuses starlark.StringDict
+}
+
+// changed returns true if any of the files of the module changed since it was loaded.
+func (m *loadedModule) changed() bool {
+	for name, modTime := range m.files {
+		fi, err := os.Stat(name)
+		if err != nil || !fi.ModTime().Equal(modTime) {
+			return true
+		}
+	}
+	return false
+}
+
+var modules = newModuleLoader("")
+
+func newModuleLoader(dir string) *moduleLoader {
+	return &moduleLoader{
+		dir:        dir,
+		registered: make(map[string]string),
+		cache:      make(map[string]*loadedModule),
+	}
+}
+
+// InitializeModules sets the directory modules are read from. Registered modules and cached modules are discarded.
+// An empty dir means that only registered modules can be loaded.
+func InitializeModules(dir string) {
+	modules = newModuleLoader(dir)
+}
+
+// RegisterModule makes a module available to the load statement, replacing any module with the same name.
+// The source is compiled to check for syntax errors before it is registered.
+func RegisterModule(name string, src string) error {
+	key, err := moduleKey(name)
+	if err != nil {
+		return err
+	}
+	if s := compile(key, src); s.err != nil {
+		return &ModuleError{Module: key, Err: s.err}
+	}
+	modules.mu.Lock()
+	defer modules.mu.Unlock()
+	modules.registered[key] = src
+	// Modules loading the old version of this module must be reloaded
+	modules.cache = make(map[string]*loadedModule)
+	return nil
+}
+
+// moduleKey normalizes a module name to the form '//dir/name.star'. Names are always relative to the module root.
+func moduleKey(name string) (string, error) {
+	name = strings.TrimPrefix(name, "//")
+	if name == "" {
+		return "", errors.New("empty module name")
+	}
+	return "/" + path.Clean("/"+name), nil
+}
+
+// loadModule implements the load statement for scope scripts. Modules are executed with the context of the
+// loading thread, so that they are canceled with the evaluation.
+func loadModule(thread *starlark.Thread, name string) (starlark.StringDict, error) {
+	loading, _ := thread.Local(loadingKey).([]string)
+	ctx, ok := thread.Local(contextKey).(context.Context)
+	if !ok {
+		ctx = context.Background()
+	}
+	m, err := modules.load(ctx, name, loading)
+	if err != nil {
+		return nil, err
+	}
+	// A module loading this module depends on its files too
+	if files, ok := thread.Local(moduleFilesKey).(map[string]time.Time); ok {
+		for name, modTime := range m.files {
+			files[name] = modTime
+		}
+	}
+	// The statuses declared by the module are named like the statuses of the loading script
+	if len(m.statuses) > 0 {
+		statuses, _ := thread.Local(statusesKey).(starlark.StringDict)
+		merged, err := mergeStatuses(statuses, m.statuses)
+		if err != nil {
+			return nil, &ModuleError{Module: name, Err: err}
+		}
+		thread.SetLocal(statusesKey, merged)
+	}
+	return m.globals, nil
+}
+
+func (l *moduleLoader) load(ctx context.Context, name string, loading []string) (*loadedModule, error) {
+	key, err := moduleKey(name)
+	if err != nil {
+		return nil, &ModuleError{Module: name, Err: err}
+	}
+	for _, m := range loading {
+		if m == key {
+			return nil, &ModuleError{Module: key, Err: errors.New("cycle in load graph")}
+		}
+	}
+
+	l.mu.Lock()
+	m, ok := l.cache[key]
+	src, registered := l.registered[key]
+	l.mu.Unlock()
+	if ok && !m.changed() {
+		return m, nil
+	}
+
+	files := make(map[string]time.Time)
+	if !registered {
+		if l.dir == "" {
+			return nil, &ModuleError{Module: key, Err: errModuleNotFound}
+		}
+		file := filepath.Join(l.dir, filepath.FromSlash(key[2:]))
+		// The modification time is read first, so a change while reading is detected on the next load
+		fi, err := os.Stat(file)
+		var b []byte
+		if err == nil {
+			b, err = os.ReadFile(file)
+		}
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				err = errModuleNotFound
+			}
+			return nil, &ModuleError{Module: key, Err: err}
+		}
+		src = string(b)
+		files[file] = fi.ModTime()
+	}
+
+	globals, statuses, err := execModule(ctx, key, src, append(loading[:len(loading):len(loading)], key), files)
+	if err != nil {
+		return nil, &ModuleError{Module: key, Err: err}
+	}
+
+	m = &loadedModule{globals: globals, files: files, statuses: statuses}
+	l.mu.Lock()
+	l.cache[key] = m
+	l.mu.Unlock()
+	return m, nil
+}
+
+// execModule executes the top level statements of a module and returns its frozen globals. Modules are shared
+// between evaluations, so the url is not available to top level statements, only to functions called by the script.
+// The files of the modules loaded by the module are added to files, and the statuses declared by the module and the
+// modules it loads are returned. Like scripts, modules are stopped when ctx is done or the execution limits are
+// exceeded.
+func execModule(ctx context.Context, key string, src string, loading []string, files map[string]time.Time) (globals, statuses starlark.StringDict, err error) {
+	thread := &starlark.Thread{
+		Name: "module " + key,
+		Print: func(thread *starlark.Thread, msg string) {
+			scriptLogger.Debug().Msg(msg)
+		},
+		Load: loadModule,
+	}
+	_, stop := limitExecution(ctx, thread)
+	defer stop()
+	thread.SetLocal(loadingKey, loading)
+	thread.SetLocal(moduleFilesKey, files)
+
+	predeclared, err := parseStatusHeader(key, src)
+	if err != nil {
+		return nil, nil, err
+	}
+	thread.SetLocal(statusesKey, predeclared)
+	globals, err = starlark.ExecFileOptions(fileOptions, thread, key, src, predeclared)
+	statuses, _ = thread.Local(statusesKey).(starlark.StringDict)
+	return globals, statuses, err
+}
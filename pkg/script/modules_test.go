@@ -0,0 +1,221 @@
+package script
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+)
+
+func Test_loadModule(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"lib/common.star": "def standardScope():\n  isScheme('http https').otherwise(ChaffDetection)\n  isSameHost().then(Include)\n",
+		"lib/cycle1.star": "load('//lib/cycle2.star', 'x')\n",
+		"lib/cycle2.star": "load('//lib/cycle1.star', 'x')\n",
+		"lib/broken.star": "load('//lib/missing.star', 'x')\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer InitializeModules("")
+	InitializeModules(dir)
+	if err := RegisterModule("lib/registered.star", "hops = 2\nhosts = ['a']\n"); err != nil {
+		t.Fatalf("RegisterModule() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		script        string
+		wantStatus    Status
+		wantErrorMsg  string
+		wantErrorCode int32
+	}{
+		{"from dir", "load('//lib/common.star', 'standardScope')\nstandardScope()", Include, "", 0},
+		{"registered", "load('//lib/registered.star', 'hops')\nmaxHopsFromSeed(hops).then(TooManyHops)", TooManyHops, "", 0},
+		{"frozen", "load('//lib/registered.star', 'hosts')\nhosts.append('b')", RuntimeException, "error executing scope script", RuntimeException.AsInt32()},
+		{"missing", "load('//lib/missing.star', 'x')\ntest(True).then(Include)", RuntimeException, "error loading module //lib/missing.star", RuntimeException.AsInt32()},
+		{"nested missing", "load('//lib/broken.star', 'x')\ntest(True).then(Include)", RuntimeException, "error loading module //lib/broken.star", RuntimeException.AsInt32()},
+		{"cycle", "load('//lib/cycle1.star', 'x')\ntest(True).then(Include)", RuntimeException, "error loading module //lib/cycle1.star", RuntimeException.AsInt32()},
+		{"outside root", "load('//../etc/passwd', 'x')\ntest(True).then(Include)", RuntimeException, "error loading module //etc/passwd", RuntimeException.AsInt32()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qUri := &frontier.QueuedUri{Uri: "http://www.example.com/", SeedUri: "http://www.example.com/", DiscoveryPath: "LLL"}
+			got := RunScopeScript(tt.name, tt.script, qUri, false)
+			if got.ExcludeReason != tt.wantStatus.AsInt32() {
+				t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v, error = %v", got.ExcludeReason, tt.wantStatus.AsInt32(), got.Error)
+			}
+			if tt.wantErrorMsg == "" {
+				if got.Error != nil {
+					t.Errorf("RunScopeScript().Error got = %v, want nil", got.Error)
+				}
+				return
+			}
+			if got.Error == nil || got.Error.Msg != tt.wantErrorMsg || got.Error.Code != tt.wantErrorCode {
+				t.Errorf("RunScopeScript().Error got = %v, want msg %q", got.Error, tt.wantErrorMsg)
+			}
+		})
+	}
+
+	g1, err := modules.load(context.Background(), "//lib/common.star", nil)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	if g2, _ := modules.load(context.Background(), "lib/common.star", nil); g2.globals["standardScope"] != g1.globals["standardScope"] {
+		t.Errorf("load() expected cached module")
+	}
+}
+
+func Test_RegisterModule(t *testing.T) {
+	defer InitializeModules("")
+	InitializeModules("")
+
+	if err := RegisterModule("//lib/bad.star", "def f(:\n"); err == nil {
+		t.Errorf("RegisterModule() expected syntax error")
+	}
+	if err := RegisterModule("//", "x = 1"); err == nil {
+		t.Errorf("RegisterModule() expected error for empty name")
+	}
+
+	script := "load('//lib/status.star', 'status')\ntest(True).then(status)"
+	qUri := &frontier.QueuedUri{Uri: "http://www.example.com/"}
+	if err := RegisterModule("//lib/status.star", "status = Blocked"); err != nil {
+		t.Fatalf("RegisterModule() error = %v", err)
+	}
+	if got := RunScopeScript("register", script, qUri, false); got.ExcludeReason != Blocked.AsInt32() {
+		t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v", got.ExcludeReason, Blocked.AsInt32())
+	}
+	if err := RegisterModule("//lib/status.star", "status = Include"); err != nil {
+		t.Fatalf("RegisterModule() error = %v", err)
+	}
+	if got := RunScopeScript("register", script, qUri, false); got.Evaluation != scopechecker.ScopeCheckResponse_INCLUDE {
+		t.Errorf("RunScopeScript().Evaluation got = %v, want %v", got.Evaluation, scopechecker.ScopeCheckResponse_INCLUDE)
+	}
+
+	// Statuses declared in the header of a module are named in the console of the loading script
+	if err := RegisterModule("//lib/status.star", "# status: ModuleStatus = -5040\nstatus = ModuleStatus"); err != nil {
+		t.Fatalf("RegisterModule() error = %v", err)
+	}
+	got := RunScopeScript("register", script, qUri, true)
+	if got.ExcludeReason != -5040 || !strings.Contains(got.Console, "match.then(ModuleStatus) status=ModuleStatus") {
+		t.Errorf("RunScopeScript() got = %v, want ModuleStatus", got)
+	}
+	conflict := "# status: ScriptStatus = -5040\nload('//lib/status.star', 'status')\ntest(True).then(status)"
+	if got := RunScopeScript("conflict", conflict, qUri, false); got.ExcludeReason != RuntimeException.AsInt32() {
+		t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v for conflicting statuses", got.ExcludeReason, RuntimeException.AsInt32())
+	}
+}
+
+func Test_loadModuleLimits(t *testing.T) {
+	defer InitializeModules("")
+	InitializeModules("")
+	defer InitializeExecutionLimits(DefaultMaxExecutionSteps, DefaultExecutionTimeout)
+	InitializeExecutionLimits(0, 50*time.Millisecond)
+
+	if err := RegisterModule("//lib/loop.star", "def f():\n  for i in range(1000000000):\n    pass\nf()\nx = 1\n"); err != nil {
+		t.Fatalf("RegisterModule() error = %v", err)
+	}
+	qUri := &frontier.QueuedUri{Uri: "http://www.example.com/"}
+	done := make(chan *scopechecker.ScopeCheckResponse)
+	go func() {
+		done <- RunScopeScript("loop", "load('//lib/loop.star', 'x')\ntest(True).then(Include)", qUri, false)
+	}()
+	select {
+	case got := <-done:
+		if got.ExcludeReason != ScriptTimeout.AsInt32() {
+			t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v, error = %v", got.ExcludeReason, ScriptTimeout.AsInt32(), got.Error)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("RunScopeScript() did not stop module with endless loop")
+	}
+
+	// A canceled evaluation stops the module too
+	InitializeExecutionLimits(0, 0)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := modules.load(ctx, "//lib/loop.star", nil); err == nil {
+		t.Errorf("load() expected error for canceled context")
+	}
+}
+
+func Test_loadModuleUrl(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "blocked.hosts"), []byte("blocked.example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = InitializeLists("") }()
+	if err := InitializeLists(dir); err != nil {
+		t.Fatalf("InitializeLists() error = %v", err)
+	}
+	defer InitializeModules("")
+	InitializeModules("")
+
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"isSameHost", "x = isSameHost()\n"},
+		{"maxHopsFromSeed", "x = maxHopsFromSeed(2)\n"},
+		{"inList", "x = inList('blocked')\n"},
+		{"looksLikeTrap", "x = looksLikeTrap()\n"},
+		{"isPrivateAddress", "x = isPrivateAddress()\n"},
+		{"removeQuery", "removeQuery('utm_source')\nx = 1\n"},
+		{"canonicalization", "canonicalization('scope')\nx = 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "//lib/" + tt.name + ".star"
+			if err := RegisterModule(key, tt.src); err != nil {
+				t.Fatalf("RegisterModule() error = %v", err)
+			}
+			if _, err := modules.load(context.Background(), key, nil); err == nil || !strings.Contains(err.Error(), errUrlNotAvailable.Error()) {
+				t.Errorf("load() error = %v, want %q", err, errUrlNotAvailable)
+			}
+		})
+	}
+}
+
+func Test_loadModuleReload(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "lib"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, src string, modTime time.Time) {
+		t.Helper()
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	modTime := time.Now().Add(-time.Minute)
+	write("lib/status.star", "status = Blocked\n", modTime)
+	write("lib/scope.star", "load('//lib/status.star', 'status')\ndef scope():\n  test(True).then(status)\n", modTime)
+	defer InitializeModules("")
+	InitializeModules(dir)
+
+	script := "load('//lib/scope.star', 'scope')\nscope()"
+	qUri := &frontier.QueuedUri{Uri: "http://www.example.com/"}
+	if got := RunScopeScript("reload", script, qUri, false); got.ExcludeReason != Blocked.AsInt32() {
+		t.Fatalf("RunScopeScript().ExcludeReason got = %v, want %v, error = %v", got.ExcludeReason, Blocked.AsInt32(), got.Error)
+	}
+
+	// A change in a module loaded by the module reloads both
+	write("lib/status.star", "status = ChaffDetection\n", modTime.Add(time.Second))
+	if got := RunScopeScript("reload", script, qUri, false); got.ExcludeReason != ChaffDetection.AsInt32() {
+		t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v, error = %v", got.ExcludeReason, ChaffDetection.AsInt32(), got.Error)
+	}
+}
@@ -0,0 +1,107 @@
+package script
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// maxCachedPatterns is the max number of compiled patterns kept in the pattern cache. The cache is cleared
+// when the limit is reached.
+const maxCachedPatterns = 10000
+
+// patternCache holds compiled regular expressions shared across evaluations.
+var patternCache = struct {
+	sync.RWMutex
+	m map[string]*regexp.Regexp
+}{m: make(map[string]*regexp.Regexp)}
+
+// compileRegex returns the compiled regular expression, compiling it only if it is not found in the cache.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	return cachedPattern("re:"+pattern, func() (*regexp.Regexp, error) {
+		return regexp.Compile(pattern)
+	})
+}
+
+// compileGlob returns a regular expression matching the whole input against the glob pattern.
+//
+// A '*' matches any sequence of characters except '/', '**' matches any sequence of characters, '?' matches
+// any single character except '/' and '[...]' matches a character class.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	return cachedPattern("glob:"+pattern, func() (*regexp.Regexp, error) {
+		return regexp.Compile(globToRegex(pattern))
+	})
+}
+
+func cachedPattern(key string, compile func() (*regexp.Regexp, error)) (*regexp.Regexp, error) {
+	patternCache.RLock()
+	re, ok := patternCache.m[key]
+	patternCache.RUnlock()
+	if ok {
+		return re, nil
+	}
+
+	re, err := compile()
+	if err != nil {
+		return nil, err
+	}
+
+	patternCache.Lock()
+	if len(patternCache.m) >= maxCachedPatterns {
+		patternCache.m = make(map[string]*regexp.Regexp)
+	}
+	patternCache.m[key] = re
+	patternCache.Unlock()
+	return re, nil
+}
+
+func globToRegex(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				b.WriteString(".*")
+				i++
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			if j := strings.IndexByte(glob[i+1:], ']'); j > 0 {
+				class := glob[i+1 : i+1+j]
+				if class[0] == '!' {
+					class = "^" + class[1:]
+				}
+				b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+				i += j + 1
+			} else {
+				b.WriteString(`\[`)
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// urlComponent returns the named component of the url.
+func urlComponent(u *UrlValue, component string) (string, error) {
+	switch component {
+	case "href":
+		return u.String(), nil
+	case "host":
+		return u.parsedUri.Hostname(), nil
+	case "path":
+		return u.parsedUri.Pathname(), nil
+	case "query":
+		return u.parsedUri.Query(), nil
+	default:
+		return "", fmt.Errorf("unknown url component '%v', must be one of href, host, path or query", component)
+	}
+}
@@ -0,0 +1,121 @@
+package script
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http/cookiejar"
+	"os"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// publicSuffixList is used for computing registered domains. It defaults to the snapshot of the Public Suffix
+// List embedded in golang.org/x/net/publicsuffix.
+var publicSuffixList cookiejar.PublicSuffixList = publicsuffix.List
+
+// LoadPublicSuffixList replaces the embedded Public Suffix List snapshot with the list in the named file.
+// The file must be in the format published at https://publicsuffix.org/list/public_suffix_list.dat.
+func LoadPublicSuffixList(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	l, err := parsePublicSuffixList(name, f)
+	if err != nil {
+		return fmt.Errorf("failed to parse public suffix list %s: %w", name, err)
+	}
+	publicSuffixList = l
+	return nil
+}
+
+// suffixRules is a Public Suffix List loaded from file.
+type suffixRules struct {
+	source     string
+	rules      map[string]bool // normal and wildcard rules, wildcards with leading '*.'
+	exceptions map[string]bool // exception rules without leading '!'
+}
+
+func parsePublicSuffixList(source string, r io.Reader) (*suffixRules, error) {
+	l := &suffixRules{
+		source:     source,
+		rules:      make(map[string]bool),
+		exceptions: make(map[string]bool),
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// Rules end at first whitespace
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "//") {
+			continue
+		}
+		rule := strings.ToLower(fields[0])
+		exception := strings.HasPrefix(rule, "!")
+		rule = strings.TrimPrefix(rule, "!")
+		wildcard := strings.HasPrefix(rule, "*.")
+		rule = strings.TrimPrefix(rule, "*.")
+		// Canonicalized hosts are punycode, so rules with internationalized labels must be too
+		rule, err := idna.ToASCII(rule)
+		if err != nil {
+			return nil, fmt.Errorf("illegal rule '%s': %w", fields[0], err)
+		}
+		switch {
+		case exception:
+			l.exceptions[rule] = true
+		case wildcard:
+			l.rules["*."+rule] = true
+		default:
+			l.rules[rule] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(l.rules) == 0 {
+		return nil, fmt.Errorf("no rules found")
+	}
+	return l, nil
+}
+
+// PublicSuffix implements cookiejar.PublicSuffixList using the algorithm described at https://publicsuffix.org/list/.
+func (l *suffixRules) PublicSuffix(domain string) string {
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		candidate := strings.Join(labels[i:], ".")
+		if l.exceptions[candidate] {
+			return strings.Join(labels[i+1:], ".")
+		}
+		if l.rules[candidate] {
+			return candidate
+		}
+		if i+1 < len(labels) && l.rules["*."+strings.Join(labels[i+1:], ".")] {
+			return candidate
+		}
+	}
+	// The default rule is '*'
+	return labels[len(labels)-1]
+}
+
+func (l *suffixRules) String() string {
+	return l.source
+}
+
+// registeredDomain returns the effective top level domain plus one label (eTLD+1) for host. IP addresses and
+// hosts which are public suffixes themselves are returned unchanged.
+func registeredDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return host
+	}
+	suffix := publicSuffixList.PublicSuffix(host)
+	if len(suffix) >= len(host) {
+		return host
+	}
+	i := strings.LastIndexByte(host[:len(host)-len(suffix)-1], '.')
+	return host[i+1:]
+}
@@ -0,0 +1,78 @@
+package script
+
+import (
+	"net/http/cookiejar"
+	"strings"
+	"testing"
+)
+
+func Test_registeredDomain(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"www.nrk.no", "nrk.no"},
+		{"tv.nrk.no", "nrk.no"},
+		{"nrk.no", "nrk.no"},
+		{"no", "no"},
+		{"www.bbc.co.uk", "bbc.co.uk"},
+		{"co.uk", "co.uk"},
+		{"foo.bar", "foo.bar"},
+		{"127.0.0.1", "127.0.0.1"},
+		{"[::1]", "[::1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := registeredDomain(tt.host); got != tt.want {
+				t.Errorf("registeredDomain() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parsePublicSuffixList(t *testing.T) {
+	l, err := parsePublicSuffixList("test", strings.NewReader(`
+// ===BEGIN ICANN DOMAINS===
+no
+priv.no
+*.ck
+!www.ck
+ålesund.no
+*.køge.dk
+com
+// ===END ICANN DOMAINS===
+`))
+	if err != nil {
+		t.Fatalf("parsePublicSuffixList() error = %v", err)
+	}
+
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		{"www.nb.no", "no"},
+		{"foo.priv.no", "priv.no"},
+		{"foo.bar.ck", "bar.ck"},
+		{"www.ck", "ck"},
+		{"foo.www.ck", "ck"},
+		{"example.com", "com"},
+		{"kommune.xn--lesund-hua.no", "xn--lesund-hua.no"},
+		{"skole.xn--lesund-hua.no", "xn--lesund-hua.no"},
+		{"www.foo.xn--kge-0na.dk", "foo.xn--kge-0na.dk"},
+		{"example.unknown", "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := l.PublicSuffix(tt.domain); got != tt.want {
+				t.Errorf("PublicSuffix() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Hosts below an internationalized suffix have different registered domains
+	defer func(l cookiejar.PublicSuffixList) { publicSuffixList = l }(publicSuffixList)
+	publicSuffixList = l
+	if a, b := registeredDomain("kommune.xn--lesund-hua.no"), registeredDomain("skole.xn--lesund-hua.no"); a == b {
+		t.Errorf("registeredDomain() got same domain %v for hosts below public suffix", a)
+	}
+}
@@ -0,0 +1,178 @@
+package script
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"veidemann-scopeservice/pkg/telemetry"
+
+	"github.com/rs/zerolog/log"
+)
+
+const scriptFileExt = ".star"
+
+// registeredScript is a compiled script loaded from the script registry.
+type registeredScript struct {
+	script *Script
+	hash   string
+}
+
+// scriptRegistry holds the compiled scripts in a directory and reloads them when they change. A script is
+// registered under its file name without the .star extension.
+type scriptRegistry struct {
+	mu      sync.RWMutex
+	scripts map[string]*registeredScript
+	failed  map[string]bool // scripts which failed to load and have no previous version
+	watcher *dirWatcher
+}
+
+var registry *scriptRegistry
+
+// InitializeScriptRegistry loads all scripts in dir and watches the directory for changes. Scope checks with an
+// empty script and the name of a registered script runs the registered script. An empty dir disables the registry.
+func InitializeScriptRegistry(dir string) error {
+	if registry != nil {
+		registry.close()
+		registry = nil
+	}
+	if dir == "" {
+		return nil
+	}
+	r, err := newScriptRegistry(dir)
+	if err != nil {
+		return err
+	}
+	registry = r
+	return nil
+}
+
+// RegisteredScript returns the registered script with the given name.
+func RegisteredScript(name string) (*Script, bool) {
+	if registry == nil {
+		return nil, false
+	}
+	return registry.get(name)
+}
+
+// Ready returns true when the script registry and the scope lists, if initialized, have loaded all their files.
+// A file which fails to load after a change keeps its previous version and does not make them unready.
+func Ready() bool {
+	return (registry == nil || registry.ready()) && (lists == nil || lists.ready())
+}
+
+func newScriptRegistry(dir string) (*scriptRegistry, error) {
+	r := &scriptRegistry{
+		scripts: make(map[string]*registeredScript),
+		failed:  make(map[string]bool),
+	}
+	isScript := func(file string) bool {
+		return filepath.Ext(file) == scriptFileExt
+	}
+	w, err := watchDir(dir, isScript, r.reload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load script registry: %w", err)
+	}
+	r.watcher = w
+	return r, nil
+}
+
+func (r *scriptRegistry) get(name string) (*Script, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.scripts[strings.TrimSuffix(name, scriptFileExt)]
+	if !ok {
+		return nil, false
+	}
+	return s.script, true
+}
+
+// reload compiles the script in file and replaces the registered version. If the file is gone the script is
+// removed from the registry. If the script does not compile, the previous version is kept.
+func (r *scriptRegistry) reload(file string) {
+	name := strings.TrimSuffix(filepath.Base(file), scriptFileExt)
+
+	src, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		r.remove(name)
+		return
+	}
+	if err != nil {
+		telemetry.RegisteredScriptReloadErrorsTotal.WithLabelValues(name).Inc()
+		log.Error().Err(err).Msgf("Failed to read script %s", file)
+		r.markFailed(name)
+		return
+	}
+
+	h := sha256.Sum256(src)
+	hash := hex.EncodeToString(h[:])
+
+	r.mu.RLock()
+	prev, ok := r.scripts[name]
+	r.mu.RUnlock()
+	if ok && prev.hash == hash {
+		return
+	}
+
+	script := compile(name, src)
+	if script.err != nil {
+		telemetry.RegisteredScriptReloadErrorsTotal.WithLabelValues(name).Inc()
+		log.Error().Err(script.err).Msgf("Failed to compile script %s, keeping previous version", file)
+		r.markFailed(name)
+		return
+	}
+
+	r.mu.Lock()
+	prev, ok = r.scripts[name]
+	r.scripts[name] = &registeredScript{script: script, hash: hash}
+	delete(r.failed, name)
+	r.mu.Unlock()
+
+	if ok {
+		telemetry.RegisteredScriptInfo.DeleteLabelValues(name, prev.hash)
+	}
+	telemetry.RegisteredScriptInfo.WithLabelValues(name, hash).Set(1)
+	log.Info().Msgf("Loaded script %s with hash %s", name, hash)
+}
+
+func (r *scriptRegistry) remove(name string) {
+	r.mu.Lock()
+	prev, ok := r.scripts[name]
+	delete(r.scripts, name)
+	delete(r.failed, name)
+	r.mu.Unlock()
+
+	if ok {
+		telemetry.RegisteredScriptInfo.DeleteLabelValues(name, prev.hash)
+		log.Info().Msgf("Removed script %s", name)
+	}
+}
+
+// markFailed marks a script as not loaded if there is no previous version of it.
+func (r *scriptRegistry) markFailed(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.scripts[name]; !ok {
+		r.failed[name] = true
+	}
+}
+
+// ready returns true if every script in the registry has been loaded.
+func (r *scriptRegistry) ready() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.failed) == 0
+}
+
+func (r *scriptRegistry) close() {
+	r.watcher.close()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for name, s := range r.scripts {
+		telemetry.RegisteredScriptInfo.DeleteLabelValues(name, s.hash)
+	}
+}
@@ -0,0 +1,115 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"veidemann-scopeservice/pkg/telemetry"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_scriptRegistry(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "registered.star")
+	writeFile := func(src string) {
+		if err := os.WriteFile(file, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// eventually waits for the registered script to evaluate to the wanted status
+	eventually := func(want Status) {
+		t.Helper()
+		qUri := &frontier.QueuedUri{Uri: "http://www.example.com/"}
+		var got int32
+		for i := 0; i < 100; i++ {
+			if got = RunScopeScript("registered", "", qUri, false).ExcludeReason; got == want.AsInt32() {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v", got, want.AsInt32())
+	}
+
+	writeFile("test(True).then(Blocked)")
+	defer func() { _ = InitializeScriptRegistry("") }()
+	if err := InitializeScriptRegistry(dir); err != nil {
+		t.Fatalf("InitializeScriptRegistry() error = %v", err)
+	}
+	eventually(Blocked)
+	if got := testutil.CollectAndCount(telemetry.RegisteredScriptInfo); got != 1 {
+		t.Errorf("RegisteredScriptInfo got %v series, want 1", got)
+	}
+
+	writeFile("test(True).then(ChaffDetection)")
+	eventually(ChaffDetection)
+
+	errors := testutil.ToFloat64(telemetry.RegisteredScriptReloadErrorsTotal.WithLabelValues("registered"))
+	writeFile("test(")
+	for i := 0; i < 100 && testutil.ToFloat64(telemetry.RegisteredScriptReloadErrorsTotal.WithLabelValues("registered")) == errors; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	eventually(ChaffDetection)
+
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+	eventually(RuntimeException)
+	if got := testutil.CollectAndCount(telemetry.RegisteredScriptInfo); got != 0 {
+		t.Errorf("RegisteredScriptInfo got %v series, want 0", got)
+	}
+
+	// Scripts with source are not looked up in the registry
+	qUri := &frontier.QueuedUri{Uri: "http://www.example.com/"}
+	if got := RunScopeScript("registered", "test(True).then(TooManyHops)", qUri, false); got.ExcludeReason != TooManyHops.AsInt32() {
+		t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v", got.ExcludeReason, TooManyHops.AsInt32())
+	}
+}
+
+func Test_scriptRegistryConfigMap(t *testing.T) {
+	// Kubernetes mounts a ConfigMap as symlinks through '..data', which is swapped atomically on updates
+	dir := t.TempDir()
+	update := func(version, src string) {
+		t.Helper()
+		data := filepath.Join(dir, version)
+		if err := os.Mkdir(data, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(data, "registered.star"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		tmp := filepath.Join(dir, "..data_tmp")
+		if err := os.Symlink(version, tmp); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	update("..v1", "test(True).then(Blocked)")
+	if err := os.Symlink(filepath.Join("..data", "registered.star"), filepath.Join(dir, "registered.star")); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = InitializeScriptRegistry("") }()
+	if err := InitializeScriptRegistry(dir); err != nil {
+		t.Fatalf("InitializeScriptRegistry() error = %v", err)
+	}
+	qUri := &frontier.QueuedUri{Uri: "http://www.example.com/"}
+	if got := RunScopeScript("registered", "", qUri, false).ExcludeReason; got != Blocked.AsInt32() {
+		t.Fatalf("RunScopeScript().ExcludeReason got = %v, want %v", got, Blocked.AsInt32())
+	}
+
+	update("..v2", "test(True).then(ChaffDetection)")
+	var got int32
+	for i := 0; i < 100; i++ {
+		if got = RunScopeScript("registered", "", qUri, false).ExcludeReason; got == ChaffDetection.AsInt32() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v", got, ChaffDetection.AsInt32())
+}
@@ -1,7 +1,9 @@
 package script
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -25,17 +27,161 @@ const (
 
 var EndOfComputation = errors.New("end of computation")
 
+const (
+	// DefaultMaxExecutionSteps is the max number of Starlark computation steps for one evaluation of a script.
+	DefaultMaxExecutionSteps = 1000000
+	// DefaultExecutionTimeout is the max wall-clock time for one evaluation of a script.
+	DefaultExecutionTimeout = 5 * time.Second
+)
+
+var (
+	maxExecutionSteps uint64 = DefaultMaxExecutionSteps
+	executionTimeout         = DefaultExecutionTimeout
+)
+
+// InitializeExecutionLimits sets the limits for each evaluation of a script. Zero means no limit.
+func InitializeExecutionLimits(maxSteps uint64, timeout time.Duration) {
+	maxExecutionSteps = maxSteps
+	executionTimeout = timeout
+}
+
+// limitExecution applies the execution limits to thread and cancels it when ctx is done. The returned context is
+// done when the timeout is exceeded, and the returned function must be called when the thread is finished.
+func limitExecution(ctx context.Context, thread *starlark.Thread) (context.Context, func()) {
+	if maxExecutionSteps > 0 {
+		thread.SetMaxExecutionSteps(maxExecutionSteps)
+	}
+	cancel := func() {}
+	if executionTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, executionTimeout)
+	}
+	stop := context.AfterFunc(ctx, func() {
+		thread.Cancel(ctx.Err().Error())
+	})
+	thread.SetLocal(contextKey, ctx)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
 var scriptLogger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: time.RFC3339}).With().
 	Timestamp().Logger().Level(zerolog.DebugLevel)
 
+var fileOptions = &syntax.FileOptions{
+	Set:            true, // allow the 'set' built-in
+	Recursion:      true, // allow while statements and recursive functions
+	GlobalReassign: true, // allow reassignment to top-level names; also, allow if/for/while at top-level
+}
+
+// Script is a compiled scope script which can be evaluated for any number of URIs. Compile errors are kept as
+// well, so that a broken script is not parsed again for every URI.
+type Script struct {
+	name string
+	prog *starlark.Program
+	// predeclared holds the statuses declared in the script header
+	predeclared starlark.StringDict
+	err         error
+}
+
+// CompileScopeScript returns the compiled scope script. Scripts are looked up in the program cache by name and
+// source hash before they are compiled. If the script registry is initialized and src is empty, the registered
+// script with the given name is returned.
+func CompileScopeScript(name string, src interface{}) *Script {
+	if s, ok := src.(string); ok && s == "" && registry != nil {
+		if p, ok := RegisteredScript(name); ok {
+			return p
+		}
+		return &Script{name: name, err: fmt.Errorf("no script named '%v' in script registry", name)}
+	}
+
+	key, cacheable := programKey(name, src)
+	if cacheable {
+		if p, ok := programCache.get(key); ok {
+			return p
+		}
+	}
+
+	t := prometheus.NewTimer(telemetry.CompileScriptSeconds.WithLabelValues(telemetry.ScriptLabel(name)))
+	p := compile(name, src)
+	t.ObserveDuration()
+
+	if cacheable {
+		programCache.add(key, p)
+	}
+	return p
+}
+
+// compile compiles a script with the statuses declared in its header.
+func compile(name string, src interface{}) *Script {
+	predeclared, err := parseStatusHeader(name, src)
+	if err != nil {
+		return &Script{name: name, err: err}
+	}
+	_, prog, err := starlark.SourceProgramOptions(fileOptions, name, src, predeclared.Has)
+	return &Script{name: name, prog: prog, predeclared: predeclared, err: err}
+}
+
+// Name returns the name the script was compiled with.
+func (s *Script) Name() string {
+	return s.name
+}
+
+// StatusName returns the name of the status with the given code. Statuses declared in the script header are only
+// known to the script, the name of other statuses is the same as Status.String.
+func (s *Script) StatusName(code int32) string {
+	return lookupStatusName(s.predeclared, Status(code))
+}
+
+// StatusByName returns the status with the given name, including the statuses declared in the script header.
+func (s *Script) StatusByName(name string) (Status, bool) {
+	return lookupStatus(s.predeclared, name)
+}
+
 // RunScopeScript runs the Scope checking script and returns the Scope status.
 func RunScopeScript(name string, src interface{}, qUri *frontier.QueuedUri, debug bool) *scopechecker.ScopeCheckResponse {
-	options := &syntax.FileOptions{
-		Set:            true, // allow the 'set' built-in
-		Recursion:      true, // allow while statements and recursive functions
-		GlobalReassign: true, // allow reassignment to top-level names; also, allow if/for/while at top-level
+	return CompileScopeScript(name, src).Run(context.Background(), qUri, debug)
+}
+
+// RunScopeScriptContext runs the Scope checking script and returns the Scope status. The evaluation is abandoned
+// when ctx is done, in which case the error from ctx is returned instead of a status.
+func RunScopeScriptContext(ctx context.Context, name string, src interface{}, qUri *frontier.QueuedUri, debug bool) (*scopechecker.ScopeCheckResponse, error) {
+	return CompileScopeScript(name, src).RunContext(ctx, qUri, debug)
+}
+
+// RunContext evaluates the compiled script for one URI like Run, but returns the error from ctx if ctx is done
+// before the evaluation completes. Exceeding the execution limits still excludes the URI with ScriptTimeout.
+func (s *Script) RunContext(ctx context.Context, qUri *frontier.QueuedUri, debug bool) (*scopechecker.ScopeCheckResponse, error) {
+	if err := ctx.Err(); err != nil {
+		countAbandoned(err)
+		return nil, err
+	}
+	result := s.run(ctx, qUri, debug, nil)
+	if err := ctx.Err(); err != nil {
+		countAbandoned(err)
+		return nil, err
 	}
+	return result, nil
+}
+
+func countAbandoned(err error) {
+	reason := "canceled"
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = "deadline_exceeded"
+	}
+	telemetry.AbandonedEvaluationsTotal.WithLabelValues(reason).Inc()
+}
 
+// Run evaluates the compiled script for one URI and returns the Scope status. It is safe to call Run concurrently.
+//
+// The evaluation is cancelled when ctx is done or when the execution limits are exceeded, in which case the
+// URI is excluded with ScriptTimeout.
+func (s *Script) Run(ctx context.Context, qUri *frontier.QueuedUri, debug bool) *scopechecker.ScopeCheckResponse {
+	return s.run(ctx, qUri, debug, nil)
+}
+
+// run evaluates the compiled script. If trace is not nil, calls to matchers are recorded in the trace.
+func (s *Script) run(ctx context.Context, qUri *frontier.QueuedUri, debug bool, trace *Trace) *scopechecker.ScopeCheckResponse {
 	consoleLog := strings.Builder{}
 
 	// Parse input URI
@@ -56,10 +202,8 @@ func RunScopeScript(name string, src interface{}, qUri *frontier.QueuedUri, debu
 
 	includeCheckUri := qUrl.AsCommonsParsedUri()
 
-	// Compile source
-	t := prometheus.NewTimer(telemetry.CompileScriptSeconds)
-	_, prog, err := starlark.SourceProgramOptions(options, name, src, starlark.StringDict{}.Has)
-	if err != nil {
+	// Check that source compiled
+	if s.err != nil {
 		return &scopechecker.ScopeCheckResponse{
 			Evaluation:      scopechecker.ScopeCheckResponse_EXCLUDE,
 			ExcludeReason:   RuntimeException.AsInt32(),
@@ -67,12 +211,11 @@ func RunScopeScript(name string, src interface{}, qUri *frontier.QueuedUri, debu
 			Error: &commons.Error{
 				Code:   RuntimeException.AsInt32(),
 				Msg:    "error parsing scope script",
-				Detail: err.Error(),
+				Detail: s.err.Error(),
 			},
 			Console: consoleLog.String(),
 		}
 	}
-	t.ObserveDuration()
 
 	// The Thread defines the behavior of the built-in 'print' function.
 	thread := &starlark.Thread{
@@ -86,6 +229,7 @@ func RunScopeScript(name string, src interface{}, qUri *frontier.QueuedUri, debu
 				scriptLogger.Debug().Msg(msg)
 			}
 		},
+		Load: loadModule,
 	}
 
 	// Set local variables
@@ -95,12 +239,51 @@ func RunScopeScript(name string, src interface{}, qUri *frontier.QueuedUri, debu
 		thread.SetLocal(a.Key, starlark.String(a.Value))
 	}
 	thread.SetLocal(debugKey, starlark.Bool(debug))
+	thread.SetLocal(statusesKey, s.predeclared)
+	if trace != nil {
+		thread.SetLocal(traceKey, trace)
+	}
+
+	// Limit execution
+	ctx, stop := limitExecution(ctx, thread)
+	defer stop()
 
 	// Execute script.
-	t = prometheus.NewTimer(telemetry.CompileScriptSeconds)
-	_, err = prog.Init(thread, nil)
+	t := prometheus.NewTimer(telemetry.ExecuteScriptSeconds.WithLabelValues(telemetry.ScriptLabel(s.name)))
+	_, err = s.prog.Init(thread, s.predeclared)
 	t.ObserveDuration()
+	if err != nil && (ctx.Err() != nil || (maxExecutionSteps > 0 && thread.ExecutionSteps() >= maxExecutionSteps)) {
+		msg := "scope script timed out"
+		if ctx.Err() == nil {
+			msg = "scope script exceeded max execution steps"
+		}
+		return &scopechecker.ScopeCheckResponse{
+			Evaluation:      scopechecker.ScopeCheckResponse_EXCLUDE,
+			ExcludeReason:   ScriptTimeout.AsInt32(),
+			IncludeCheckUri: includeCheckUri,
+			Error: &commons.Error{
+				Code:   ScriptTimeout.AsInt32(),
+				Msg:    msg,
+				Detail: err.Error(),
+			},
+			Console: consoleLog.String(),
+		}
+	}
 	if err != nil {
+		moduleErr := new(ModuleError)
+		if errors.As(err, &moduleErr) {
+			return &scopechecker.ScopeCheckResponse{
+				Evaluation:      scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason:   RuntimeException.AsInt32(),
+				IncludeCheckUri: includeCheckUri,
+				Error: &commons.Error{
+					Code:   RuntimeException.AsInt32(),
+					Msg:    "error loading module " + moduleErr.Module,
+					Detail: moduleErr.Err.Error(),
+				},
+				Console: consoleLog.String(),
+			}
+		}
 		evalErr := new(starlark.EvalError)
 		if errors.As(err, &evalErr) {
 			if errors.Is(evalErr, EndOfComputation) {
@@ -146,9 +329,9 @@ func RunScopeScript(name string, src interface{}, qUri *frontier.QueuedUri, debu
 		}
 	}
 
-	s, ok := thread.Local(resultKey).(Status)
+	status, ok := thread.Local(resultKey).(Status)
 	if ok {
-		if s == 0 {
+		if status == 0 {
 			return &scopechecker.ScopeCheckResponse{
 				Evaluation:      scopechecker.ScopeCheckResponse_INCLUDE,
 				IncludeCheckUri: includeCheckUri,
@@ -157,7 +340,7 @@ func RunScopeScript(name string, src interface{}, qUri *frontier.QueuedUri, debu
 		} else {
 			return &scopechecker.ScopeCheckResponse{
 				Evaluation:      scopechecker.ScopeCheckResponse_EXCLUDE,
-				ExcludeReason:   s.AsInt32(),
+				ExcludeReason:   status.AsInt32(),
 				IncludeCheckUri: includeCheckUri,
 				Console:         consoleLog.String(),
 			}
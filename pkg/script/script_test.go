@@ -0,0 +1,133 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"veidemann-scopeservice/pkg/telemetry"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func Test_executionLimits(t *testing.T) {
+	defer InitializeExecutionLimits(DefaultMaxExecutionSteps, DefaultExecutionTimeout)
+
+	loop := `
+def loop():
+    for i in range(1000000000):
+        pass
+loop()`
+
+	qUri := &frontier.QueuedUri{Uri: "http://foo.bar/"}
+
+	tests := []struct {
+		name     string
+		maxSteps uint64
+		timeout  time.Duration
+		ctx      func() (context.Context, context.CancelFunc)
+		script   string
+		want     Status
+		wantMsg  string
+	}{
+		{"maxSteps", 1000, 0, background, loop, ScriptTimeout, "scope script exceeded max execution steps"},
+		{"timeout", 0, 50 * time.Millisecond, background, loop, ScriptTimeout, "scope script timed out"},
+		{"contextDeadline", 0, 0, deadline(50 * time.Millisecond), loop, ScriptTimeout, "scope script timed out"},
+		{"withinLimits", 1000, time.Second, background, "test(True).then(Include)", Include, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InitializeExecutionLimits(tt.maxSteps, tt.timeout)
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			got := CompileScopeScript(tt.name, tt.script).Run(ctx, qUri, false)
+			if got.ExcludeReason != tt.want.AsInt32() {
+				t.Errorf("Run().ExcludeReason got = %v, want %v", got.ExcludeReason, tt.want.AsInt32())
+			}
+			if tt.want == Include {
+				if got.Evaluation != scopechecker.ScopeCheckResponse_INCLUDE {
+					t.Errorf("Run().Evaluation got = %v, want %v", got.Evaluation, scopechecker.ScopeCheckResponse_INCLUDE)
+				}
+				return
+			}
+			if got.Error == nil || got.Error.Msg != tt.wantMsg {
+				t.Errorf("Run().Error got = %v, want msg %v", got.Error, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRunScopeScriptContext(t *testing.T) {
+	defer InitializeExecutionLimits(DefaultMaxExecutionSteps, DefaultExecutionTimeout)
+
+	loop := `
+def loop():
+    for i in range(1000000000):
+        pass
+loop()`
+
+	qUri := &frontier.QueuedUri{Uri: "http://foo.bar/"}
+
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		ctx       func() (context.Context, context.CancelFunc)
+		script    string
+		want      Status
+		wantErr   error
+		abandoned string
+	}{
+		{"own timeout", 50 * time.Millisecond, background, loop, ScriptTimeout, nil, ""},
+		{"deadline exceeded", time.Second, deadline(50 * time.Millisecond), loop, 0, context.DeadlineExceeded, "deadline_exceeded"},
+		{"canceled", time.Second, canceled, loop, 0, context.Canceled, "canceled"},
+		{"completed", time.Second, background, "test(True).then(Blocked)", Blocked, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InitializeExecutionLimits(0, tt.timeout)
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			var abandonedBefore float64
+			if tt.abandoned != "" {
+				abandonedBefore = testutil.ToFloat64(telemetry.AbandonedEvaluationsTotal.WithLabelValues(tt.abandoned))
+			}
+			got, err := RunScopeScriptContext(ctx, tt.name, tt.script, qUri, false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunScopeScriptContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if got != nil {
+					t.Errorf("RunScopeScriptContext() got = %v, want nil", got)
+				}
+				if n := testutil.ToFloat64(telemetry.AbandonedEvaluationsTotal.WithLabelValues(tt.abandoned)) - abandonedBefore; n != 1 {
+					t.Errorf("AbandonedEvaluationsTotal{reason=%v} got %v, want 1", tt.abandoned, n)
+				}
+				return
+			}
+			if got.ExcludeReason != tt.want.AsInt32() {
+				t.Errorf("RunScopeScriptContext().ExcludeReason got = %v, want %v", got.ExcludeReason, tt.want.AsInt32())
+			}
+		})
+	}
+}
+
+func background() (context.Context, context.CancelFunc) {
+	return context.WithCancel(context.Background())
+}
+
+func deadline(d time.Duration) func() (context.Context, context.CancelFunc) {
+	return func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), d)
+	}
+}
+
+func canceled() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	return ctx, cancel
+}
@@ -0,0 +1,30 @@
+// Package scripttest runs scope script tests from go test.
+package scripttest
+
+import (
+	"testing"
+
+	"veidemann-scopeservice/pkg/script"
+)
+
+// Run runs the tests in testFile against the scope script in scriptFile. Each test function in the test file is
+// run as a subtest of t. The built-in canonicalization profiles are used unless they are already initialized.
+func Run(t *testing.T, scriptFile string, testFile string) {
+	t.Helper()
+	if script.ScopeCanonicalizationProfile == nil {
+		if err := script.InitializeCanonicalizationProfiles(false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, err := script.RunTestFile(scriptFile, testFile)
+	if err != nil {
+		t.Fatalf("failed to run %s: %v", testFile, err)
+	}
+	for _, r := range results {
+		t.Run(r.Name, func(t *testing.T) {
+			for _, f := range r.Failures {
+				t.Error(f)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package scripttest
+
+import "testing"
+
+func TestRun(t *testing.T) {
+	Run(t, "testdata/scope.star", "testdata/scope_test.star")
+}
@@ -3,6 +3,7 @@ package script
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/nlnwa/veidemann-api/go/commons/v1"
 	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
@@ -18,6 +19,9 @@ import (
 //   - -4002 TOO_MANY_TRANSITIVE_HOPS    The URI is too many embed/transitive hops away from the last URI in scope.
 //   - -5001 BLOCKED                     Blocked from fetch by user setting.
 //   - -5002 BLOCKED_BY_CUSTOM_PROCESSOR Blocked by a custom processor.
+//   - -5003 SCRIPT_TIMEOUT              Evaluation of script was cancelled by timeout or step limit.
+//
+// Custom status codes can be added with RegisterStatus, see custom_status.go.
 func init() {
 	for k, v := range statusValues {
 		starlark.Universe[k] = v
@@ -33,10 +37,15 @@ var (
 	TooManyTransitiveHops    Status = -4002
 	Blocked                  Status = -5001
 	BlockedByCustomProcessor Status = -5002
+	ScriptTimeout            Status = -5003
 )
 
+// statusMu guards statusNames and statusValues which are extended with custom status codes.
+var statusMu sync.RWMutex
+
 var statusNames = map[Status]string{
 	Include:                  "Include",
+	ScriptTimeout:            "ScriptTimeout",
 	RuntimeException:         "RuntimeException",
 	IllegalUri:               "IllegalUri",
 	ChaffDetection:           "ChaffDetection",
@@ -48,6 +57,7 @@ var statusNames = map[Status]string{
 
 var statusValues = map[string]Status{
 	"Include":                  Include,
+	"ScriptTimeout":            ScriptTimeout,
 	"RuntimeException":         RuntimeException,
 	"IllegalUri":               IllegalUri,
 	"ChaffDetection":           ChaffDetection,
@@ -59,12 +69,24 @@ var statusValues = map[string]Status{
 
 type Status int32
 
+// StatusByName returns the status with the given name as used in scope scripts, e.g. 'Blocked'.
+func StatusByName(name string) (Status, bool) {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	s, ok := statusValues[name]
+	return s, ok
+}
+
 // Implement starlark.Value interface
 func (s Status) Type() string          { return "end of computation status" }
 func (s Status) Freeze()               {} // immutable
 func (s Status) Truth() starlark.Bool  { return true }
 func (s Status) Hash() (uint32, error) { return starlark.MakeUint(uint(s)).Hash() }
-func (s Status) String() string        { return statusNames[s] }
+func (s Status) String() string {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	return statusNames[s]
+}
 
 // Implement starlark.Unpacker interface
 func (s *Status) Unpack(v starlark.Value) error {
@@ -72,7 +94,7 @@ func (s *Status) Unpack(v starlark.Value) error {
 	case Status:
 		*s = val
 	case starlark.String:
-		value, ok := statusValues[val.GoString()]
+		value, ok := StatusByName(val.GoString())
 		if !ok {
 			return errors.New("Illegal type " + val.String())
 		}
@@ -0,0 +1,100 @@
+package script
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// paramPatterns matches names of query and path parameters which should be removed from a url.
+//
+// A pattern enclosed in slashes, e.g. '/^ga_[0-9]+$/', is a regular expression. A pattern ending with '*',
+// e.g. 'utm_*', matches names with that prefix. Any other pattern must be equal to the name. Exact and prefix
+// patterns are case-insensitive.
+type paramPatterns struct {
+	names    map[string]bool
+	prefixes []string
+	regexes  []*regexp.Regexp
+}
+
+func newParamPatterns(patterns []string) (*paramPatterns, error) {
+	p := &paramPatterns{names: make(map[string]bool)}
+	for _, pattern := range patterns {
+		switch {
+		case len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+			re, err := compileRegex(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("illegal parameter pattern '%s': %w", pattern, err)
+			}
+			p.regexes = append(p.regexes, re)
+		case strings.HasSuffix(pattern, "*"):
+			p.prefixes = append(p.prefixes, strings.ToLower(strings.TrimSuffix(pattern, "*")))
+		case pattern != "":
+			p.names[strings.ToLower(pattern)] = true
+		}
+	}
+	return p, nil
+}
+
+func (p *paramPatterns) match(name string) bool {
+	lower := strings.ToLower(name)
+	if p.names[lower] {
+		return true
+	}
+	for _, prefix := range p.prefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	for _, re := range p.regexes {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// strip removes matching query parameters and matching path parameters like ';jsessionid=...' from u.
+func (p *paramPatterns) strip(u *url.Url) {
+	var names []string
+	u.SearchParams().Iterate(func(pair *url.NameValuePair) {
+		if p.match(pair.Name) {
+			names = append(names, pair.Name)
+		}
+	})
+	for _, name := range names {
+		u.SearchParams().Delete(name)
+	}
+	if len(names) > 0 && u.Query() == "" {
+		u.SetSearch("")
+	}
+
+	path := u.Pathname()
+	if strings.Contains(path, ";") {
+		if stripped := p.stripPathParams(path); stripped != path {
+			u.SetPathname(stripped)
+		}
+	}
+}
+
+// stripPathParams removes matching ';name=value' parameters from each path segment.
+func (p *paramPatterns) stripPathParams(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		params := strings.Split(segment, ";")
+		if len(params) == 1 {
+			continue
+		}
+		kept := params[:1]
+		for _, param := range params[1:] {
+			name, _, _ := strings.Cut(param, "=")
+			if !p.match(name) {
+				kept = append(kept, param)
+			}
+		}
+		segments[i] = strings.Join(kept, ";")
+	}
+	return strings.Join(segments, "/")
+}
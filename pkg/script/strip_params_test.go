@@ -0,0 +1,81 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+func Test_paramPatterns_strip(t *testing.T) {
+	defer InitializeCanonicalizationProfiles(false)
+	if err := InitializeCanonicalizationProfiles(false, "jsessionid", "PHPSESSID", "utm_*", "fbclid", "/^ga_[0-9]+$/"); err != nil {
+		t.Fatalf("InitializeCanonicalizationProfiles() error = %v", err)
+	}
+
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"http://foo.bar/aa?jsessionid=1&foo", "http://foo.bar/aa?foo"},
+		{"http://foo.bar/aa?JSESSIONID=1&phpsessid=2", "http://foo.bar/aa"},
+		{"http://foo.bar/aa?utm_source=x&utm_medium=y&id=3&fbclid=abc", "http://foo.bar/aa?id=3"},
+		{"http://foo.bar/aa?ga_123=1&ga_x=2", "http://foo.bar/aa?ga_x=2"},
+		{"http://foo.bar/aa;jsessionid=ABC123?id=1", "http://foo.bar/aa?id=1"},
+		{"http://foo.bar/aa;jsessionid=ABC123/bb;type=a", "http://foo.bar/aa/bb;type=a"},
+		{"http://foo.bar/aa;foo=1;jsessionid=1", "http://foo.bar/aa;foo=1"},
+		{"http://foo.bar/aa?session=1", "http://foo.bar/aa?session=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			for name, profile := range map[string]url.Parser{ScopeProfileName: ScopeCanonicalizationProfile, CrawlProfileName: CrawlCanonicalizationProfile} {
+				got, err := profile.Parse(tt.uri)
+				if err != nil {
+					t.Fatalf("%s Parse() error = %v", name, err)
+				}
+				if got.String() != tt.want {
+					t.Errorf("%s Parse() got = %v, want %v", name, got.String(), tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_stripParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		uri    string
+	}{
+		{"exact", "stripParams('jsessionid')\nisUrl('http://foo.bar/aa?foo').then(Include)", "http://foo.bar/aa?jsessionid=1&foo"},
+		{"prefix", "stripParams('utm_* fbclid')\nisUrl('http://foo.bar/aa').then(Include)", "http://foo.bar/aa?utm_source=x&fbclid=1"},
+		{"path", "stripParams('jsessionid')\nisUrl('http://foo.bar/aa?id=1').then(Include)", "http://foo.bar/aa;jsessionid=1?id=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, &frontier.QueuedUri{Uri: tt.uri}, false)
+			if got.Evaluation != scopechecker.ScopeCheckResponse_INCLUDE {
+				t.Errorf("RunScopeScript().Evaluation got = %v, want %v, error: %v", got.Evaluation, scopechecker.ScopeCheckResponse_INCLUDE, got.Error)
+			}
+		})
+	}
+}
+
+func TestInitializeCanonicalizationProfiles_illegalRegex(t *testing.T) {
+	defer InitializeCanonicalizationProfiles(false)
+	if err := InitializeCanonicalizationProfiles(false, "jsessionid"); err != nil {
+		t.Fatalf("InitializeCanonicalizationProfiles() error = %v", err)
+	}
+	if err := InitializeCanonicalizationProfiles(false, "/ga_[/"); err == nil {
+		t.Errorf("InitializeCanonicalizationProfiles() expected error for illegal regular expression")
+	}
+	// The previous profiles are kept
+	u, err := ScopeCanonicalizationProfile.Parse("http://foo.bar/aa?jsessionid=1&ga_[=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.String(); got != "http://foo.bar/aa?ga_[=2" {
+		t.Errorf("Parse() got = %v, want the previous profile", got)
+	}
+}
@@ -0,0 +1,182 @@
+package script
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/nlnwa/whatwg-url/url"
+)
+
+// maxCachedSurtPrefixLists is the max number of prefix lists kept in the SURT prefix cache. The cache is cleared
+// when the limit is reached.
+const maxCachedSurtPrefixLists = 1000
+
+// Surt returns the url in Sort-friendly URI Reordering Transform (SURT) form as used by Heritrix,
+// e.g. 'http://(no,nb,www,)/path?query'.
+func (u *UrlValue) Surt() string {
+	if u.parsedUri == nil {
+		return u.qUri.Uri
+	}
+	return Surt(u.parsedUri)
+}
+
+// Surt returns a parsed url in SURT form, see UrlValue.Surt.
+func Surt(p *url.Url) string {
+	var b strings.Builder
+	b.WriteString(p.Protocol())
+	b.WriteString("//(")
+	host := p.Hostname()
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		b.WriteString(host)
+	} else if host != "" {
+		labels := strings.Split(host, ".")
+		for i := len(labels) - 1; i >= 0; i-- {
+			b.WriteString(labels[i])
+			b.WriteString(",")
+		}
+	}
+	if p.Port() != "" {
+		b.WriteString(":")
+		b.WriteString(p.Port())
+	}
+	if p.Username() != "" {
+		b.WriteString("@")
+		b.WriteString(p.Username())
+		if p.Password() != "" {
+			b.WriteString(":")
+			b.WriteString(p.Password())
+		}
+	}
+	b.WriteString(")")
+	b.WriteString(p.Pathname())
+	b.WriteString(p.Search())
+	b.WriteString(p.Hash())
+	return b.String()
+}
+
+// surtPrefixes is a trie of SURT prefixes.
+type surtPrefixes struct {
+	children map[byte]*surtPrefixes
+	prefix   string // set if a prefix ends at this node
+}
+
+// newSurtPrefixes indexes a space or newline separated list of prefixes. Prefixes not in SURT form are plain URLs
+// which are converted to the prefix they imply, see prefixFromPlain. A leading '+' is ignored. Like in Heritrix,
+// https is indexed as http, and surts must be converted with surtForComparison before they are matched.
+func newSurtPrefixes(prefixes string) (*surtPrefixes, error) {
+	root := &surtPrefixes{}
+	for _, p := range strings.Fields(prefixes) {
+		// Heritrix surt prefix files mark prefixes with a leading '+'
+		p = strings.TrimPrefix(p, "+")
+		if strings.Contains(p, "(") {
+			// Scheme and authority are case-insensitive
+			if i := strings.Index(p, ")"); i >= 0 {
+				p = strings.ToLower(p[:i]) + p[i:]
+			} else {
+				p = strings.ToLower(p)
+			}
+		} else {
+			var err error
+			if p, err = prefixFromPlain(p); err != nil {
+				return nil, err
+			}
+		}
+		root.add(surtForComparison(p))
+	}
+	return root, nil
+}
+
+// prefixFromPlain returns the SURT prefix implied by a plain URL, following Heritrix' SurtPrefixSet.prefixFromPlain:
+// a URL without a path includes all subdomains ('http://nb.no' is 'http://(no,nb,'), and a URL with a path is cut
+// after the last '/' of the path ('http://nb.no/a/b?c=/d' is 'http://(no,nb,)/a/').
+func prefixFromPlain(u string) (string, error) {
+	parsed, err := ScopeCanonicalizationProfile.Parse(u)
+	if err != nil {
+		return "", err
+	}
+	// Only the path is cut, a '/' in the query or fragment is not a path separator
+	p := Surt(parsed)
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	// The slash added by canonicalization is not part of the prefix
+	if i := strings.IndexAny(u, "?#"); i >= 0 {
+		u = u[:i]
+	}
+	if !strings.HasSuffix(u, "/") {
+		p = strings.TrimSuffix(p, "/")
+	}
+	if strings.HasSuffix(p, ")") {
+		return strings.TrimSuffix(p, ")"), nil
+	}
+	return p[:strings.LastIndex(p, "/")+1], nil
+}
+
+// surtForComparison returns the surt with https replaced by http, so that prefixes match both schemes like in
+// Heritrix.
+func surtForComparison(surt string) string {
+	if strings.HasPrefix(surt, "https://") {
+		return "http://" + surt[len("https://"):]
+	}
+	return surt
+}
+
+func (t *surtPrefixes) add(prefix string) {
+	n := t
+	for i := 0; i < len(prefix); i++ {
+		if n.children == nil {
+			n.children = make(map[byte]*surtPrefixes)
+		}
+		c, ok := n.children[prefix[i]]
+		if !ok {
+			c = &surtPrefixes{}
+			n.children[prefix[i]] = c
+		}
+		n = c
+	}
+	n.prefix = prefix
+}
+
+// match returns the shortest prefix of surt found in the trie.
+func (t *surtPrefixes) match(surt string) (string, bool) {
+	n := t
+	for i := 0; i < len(surt); i++ {
+		n = n.children[surt[i]]
+		if n == nil {
+			return "", false
+		}
+		if n.prefix != "" {
+			return n.prefix, true
+		}
+	}
+	return "", false
+}
+
+// surtPrefixCache holds indexed prefix lists so that a list is only indexed once for a script.
+var surtPrefixCache = struct {
+	sync.Mutex
+	m map[string]*surtPrefixes
+}{m: make(map[string]*surtPrefixes)}
+
+func cachedSurtPrefixes(prefixes string) (*surtPrefixes, error) {
+	surtPrefixCache.Lock()
+	t, ok := surtPrefixCache.m[prefixes]
+	surtPrefixCache.Unlock()
+	if ok {
+		return t, nil
+	}
+
+	t, err := newSurtPrefixes(prefixes)
+	if err != nil {
+		return nil, err
+	}
+
+	surtPrefixCache.Lock()
+	if len(surtPrefixCache.m) >= maxCachedSurtPrefixLists {
+		surtPrefixCache.m = make(map[string]*surtPrefixes)
+	}
+	surtPrefixCache.m[prefixes] = t
+	surtPrefixCache.Unlock()
+	return t, nil
+}
@@ -0,0 +1,96 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+)
+
+func TestUrlValue_Surt(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"http://www.nb.no/", "http://(no,nb,www,)/"},
+		{"https://WWW.nb.no:8443/a/b?b=2&a=1#frag", "https://(no,nb,www,:8443)/a/b?a=1&b=2"},
+		{"http://127.0.0.1/foo", "http://(127.0.0.1)/foo"},
+		{"http://[::1]:8080/", "http://([::1]:8080)/"},
+		{"foo.bar/aa bb/cc", "http://(bar,foo,)/aa%20bb/cc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			u, err := Url(&frontier.QueuedUri{Uri: tt.uri})
+			if err != nil {
+				t.Fatalf("Url() error = %v", err)
+			}
+			if got := u.Surt(); got != tt.want {
+				t.Errorf("Surt() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_surtPrefixes(t *testing.T) {
+	prefixes, err := newSurtPrefixes(`
++http://(no,nb,
+http://(NO,Vg,www,)/Sport/
+https://www.example.com/a/`)
+	if err != nil {
+		t.Fatalf("newSurtPrefixes() error = %v", err)
+	}
+
+	tests := []struct {
+		surt       string
+		wantPrefix string
+		wantMatch  bool
+	}{
+		{"http://(no,nb,)/", "http://(no,nb,", true},
+		{"http://(no,nb,www,)/foo", "http://(no,nb,", true},
+		{"http://(no,nbx,)/", "", false},
+		{"https://(no,nb,)/", "http://(no,nb,", true},
+		{"ftp://(no,nb,)/", "", false},
+		{"http://(no,vg,www,)/Sport/fotball", "http://(no,vg,www,)/Sport/", true},
+		{"http://(no,vg,www,)/sport/fotball", "", false},
+		{"http://(com,example,www,)/a/b", "http://(com,example,www,)/a/", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.surt, func(t *testing.T) {
+			prefix, match := prefixes.match(surtForComparison(tt.surt))
+			if match != tt.wantMatch || prefix != tt.wantPrefix {
+				t.Errorf("match() got = %v, %v, want %v, %v", prefix, match, tt.wantPrefix, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func Test_prefixFromPlain(t *testing.T) {
+	// Prefixes implied by Heritrix' SurtPrefixSet.prefixFromPlainForceHttp
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"http://www.archive.org", "http://(org,archive,www,"},
+		{"http://www.archive.org/", "http://(org,archive,www,)/"},
+		{"http://www.archive.org/movies", "http://(org,archive,www,)/"},
+		{"http://www.archive.org/movies/", "http://(org,archive,www,)/movies/"},
+		{"http://www.archive.org/movies/index.html", "http://(org,archive,www,)/movies/"},
+		{"www.archive.org", "http://(org,archive,www,"},
+		{"https://nb.no", "http://(no,nb,"},
+		{"https://nb.no/a/", "http://(no,nb,)/a/"},
+		{"http://nb.no:8080", "http://(no,nb,:8080"},
+		{"http://a.com/x?r=/y", "http://(com,a,)/"},
+		{"http://a.com/x/y?r=/z#/f", "http://(com,a,)/x/"},
+		{"http://a.com/x/?r=/", "http://(com,a,)/x/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			got, err := prefixFromPlain(tt.uri)
+			if err != nil {
+				t.Fatalf("prefixFromPlain() error = %v", err)
+			}
+			if got = surtForComparison(got); got != tt.want {
+				t.Errorf("prefixFromPlain() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
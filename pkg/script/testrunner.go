@@ -0,0 +1,190 @@
+package script
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nlnwa/veidemann-api/go/config/v1"
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"go.starlark.net/starlark"
+)
+
+const (
+	testTargetKey   = "testTarget"
+	testFailuresKey = "testFailures"
+	testPrefix      = "test_"
+)
+
+// TestResult is the result of one test function in a script test file.
+type TestResult struct {
+	Name     string
+	Failures []string
+}
+
+// Passed returns true if no assertions in the test failed.
+func (r TestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// testTarget is the scope script the assertions in a test file are evaluated against.
+type testTarget struct {
+	name string
+	src  interface{}
+	// statuses are the statuses declared in the header of the test file
+	statuses starlark.StringDict
+}
+
+// testPredeclared are the builtins only available in test files.
+var testPredeclared = starlark.StringDict{
+	"assertScope": starlark.NewBuiltin("assertScope", assertScope),
+}
+
+// RunTestFile runs the tests in testFile against the scope script in scriptFile.
+func RunTestFile(scriptFile string, testFile string) ([]TestResult, error) {
+	src, err := os.ReadFile(scriptFile)
+	if err != nil {
+		return nil, err
+	}
+	testSrc, err := os.ReadFile(testFile)
+	if err != nil {
+		return nil, err
+	}
+	return RunTests(filepath.Base(scriptFile), src, filepath.Base(testFile), testSrc)
+}
+
+// RunTests runs all functions named test_* in the test script against the scope script. Tests are run in the
+// order they are defined. An error is returned if the test script itself could not be executed.
+func RunTests(name string, src interface{}, testName string, testSrc interface{}) ([]TestResult, error) {
+	thread := &starlark.Thread{
+		Name: testName,
+		Print: func(thread *starlark.Thread, msg string) {
+			scriptLogger.Debug().Msg(msg)
+		},
+		Load: loadModule,
+	}
+	_, stop := limitExecution(context.Background(), thread)
+	defer stop()
+	statuses, err := parseStatusHeader(testName, testSrc)
+	if err != nil {
+		return nil, err
+	}
+	thread.SetLocal(statusesKey, statuses)
+	predeclared := starlark.StringDict{}
+	for k, v := range statuses {
+		predeclared[k] = v
+	}
+	for k, v := range testPredeclared {
+		predeclared[k] = v
+	}
+	globals, err := starlark.ExecFileOptions(fileOptions, thread, testName, testSrc, predeclared)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []*starlark.Function
+	for k, v := range globals {
+		if fn, ok := v.(*starlark.Function); ok && strings.HasPrefix(k, testPrefix) {
+			tests = append(tests, fn)
+		}
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Position().Line < tests[j].Position().Line
+	})
+
+	target := &testTarget{name: name, src: src, statuses: statuses}
+	results := make([]TestResult, 0, len(tests))
+	for _, fn := range tests {
+		results = append(results, runTest(target, fn))
+	}
+	return results, nil
+}
+
+func runTest(target *testTarget, fn *starlark.Function) TestResult {
+	var failures []string
+	thread := &starlark.Thread{
+		Name: fn.Name(),
+		Print: func(thread *starlark.Thread, msg string) {
+			scriptLogger.Debug().Msg(msg)
+		},
+		Load: loadModule,
+	}
+	_, stop := limitExecution(context.Background(), thread)
+	defer stop()
+	thread.SetLocal(testTargetKey, target)
+	thread.SetLocal(testFailuresKey, &failures)
+	thread.SetLocal(statusesKey, target.statuses)
+
+	if _, err := starlark.Call(thread, fn, nil, nil); err != nil {
+		evalErr := new(starlark.EvalError)
+		if errors.As(err, &evalErr) {
+			failures = append(failures, evalErr.Backtrace())
+		} else {
+			failures = append(failures, err.Error())
+		}
+	}
+	return TestResult{Name: fn.Name(), Failures: failures}
+}
+
+// assertScope evaluates the scope script for a synthetic queued uri and records a failure if the status is not
+// the expected status.
+func assertScope(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var uri, seed, path, referrer string
+	var annotations *starlark.Dict
+	expect := statusArg{thread: thread, status: Include}
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "uri", &uri, "seed?", &seed, "path?", &path,
+		"referrer?", &referrer, "annotations?", &annotations, "expect?", &expect); err != nil {
+		return nil, err
+	}
+	target, ok := thread.Local(testTargetKey).(*testTarget)
+	if !ok {
+		return nil, fmt.Errorf("%s can only be called from test functions", b.Name())
+	}
+	failures := thread.Local(testFailuresKey).(*[]string)
+
+	qUri := &frontier.QueuedUri{
+		Uri:           uri,
+		SeedUri:       seed,
+		DiscoveryPath: path,
+		Referrer:      referrer,
+	}
+	if annotations != nil {
+		for _, item := range annotations.Items() {
+			k, ok1 := starlark.AsString(item[0])
+			v, ok2 := starlark.AsString(item[1])
+			if !ok1 || !ok2 {
+				return nil, fmt.Errorf("%s: annotations must be a dict of strings", b.Name())
+			}
+			qUri.Annotation = append(qUri.Annotation, &config.Annotation{Key: k, Value: v})
+		}
+	}
+
+	ctx, ok := thread.Local(contextKey).(context.Context)
+	if !ok {
+		ctx = context.Background()
+	}
+	compiled := CompileScopeScript(target.name, target.src)
+	result := compiled.Run(ctx, qUri, false)
+	if result.ExcludeReason == expect.status.AsInt32() {
+		return starlark.None, nil
+	}
+
+	// Run again with debug to get the console log
+	result = compiled.Run(ctx, qUri, true)
+	msg := strings.Builder{}
+	_, _ = fmt.Fprintf(&msg, "%v: assertScope(%q) got %v (%d), want %v (%d)",
+		thread.CallFrame(1).Pos, uri, compiled.StatusName(result.ExcludeReason), result.ExcludeReason,
+		statusName(thread, expect.status), expect.status.AsInt32())
+	if result.Error != nil {
+		_, _ = fmt.Fprintf(&msg, "\n  error: %s: %s", result.Error.Msg, result.Error.Detail)
+	}
+	if result.Console != "" {
+		msg.WriteString("\n  console:\n    " + strings.ReplaceAll(strings.TrimSuffix(result.Console, "\n"), "\n", "\n    "))
+	}
+	*failures = append(*failures, msg.String())
+	return starlark.None, nil
+}
@@ -0,0 +1,104 @@
+package script
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunTests(t *testing.T) {
+	src := "isSameHost().then(Include)\n"
+	testSrc := `
+def test_pass():
+    assertScope("http://a.com/b", seed="http://a.com/")
+
+def test_fail():
+    assertScope("http://b.com/", seed="http://a.com/", expect=Include)
+    assertScope("http://a.com/", seed="http://a.com/", expect=Blocked)
+
+def test_error():
+    fail("oops")
+
+def helper():
+    assertScope("http://b.com/")
+`
+	results, err := RunTests("scope.star", src, "scope_test.star", testSrc)
+	if err != nil {
+		t.Fatalf("RunTests() error = %v", err)
+	}
+
+	want := []struct {
+		name     string
+		failures []string
+	}{
+		{"test_pass", nil},
+		{"test_fail", []string{
+			"scope_test.star:6:16: assertScope(\"http://b.com/\") got Blocked (-5001), want Include (0)\n  error: Blocked: No scope rules matched\n  console:\n    scope.star:1:11 isSameHost() host=b.com, seedHost=a.com, match=false",
+			"scope_test.star:7:16: assertScope(\"http://a.com/\") got Include (0), want Blocked (-5001)\n  console:\n    scope.star:1:11 isSameHost() host=a.com, seedHost=a.com, match=true\n    scope.star:1:18 match.then(Include) status=Include",
+		}},
+		{"test_error", []string{"Error in fail: fail: oops"}},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("RunTests() got %v results, want %v: %v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		got := results[i]
+		if got.Name != w.name {
+			t.Errorf("RunTests()[%d].Name got = %v, want %v", i, got.Name, w.name)
+		}
+		if got.Passed() != (len(w.failures) == 0) || len(got.Failures) != len(w.failures) {
+			t.Errorf("RunTests()[%d].Failures got = %q, want %q", i, got.Failures, w.failures)
+			continue
+		}
+		for j := range w.failures {
+			if !strings.Contains(got.Failures[j], w.failures[j]) {
+				t.Errorf("RunTests()[%d].Failures[%d] got:\n%v\nwant:\n%v", i, j, got.Failures[j], w.failures[j])
+			}
+		}
+	}
+
+	results, err = RunTests("param.star", "test(param('key') == 'value').then(Include)", "param_test.star",
+		"def test_annotations():\n    assertScope('http://a.com/', annotations={'key': 'value'})\n")
+	if err != nil || len(results) != 1 || !results[0].Passed() {
+		t.Errorf("RunTests() with annotations got = %v, error = %v", results, err)
+	}
+
+	if _, err := RunTests("scope.star", src, "scope_test.star", "assertScope('http://a.com/')"); err == nil {
+		t.Errorf("RunTests() expected error when assertScope is called outside test function")
+	}
+}
+
+func TestRunTestsLimits(t *testing.T) {
+	defer InitializeExecutionLimits(DefaultMaxExecutionSteps, DefaultExecutionTimeout)
+	loop := "def loop():\n    for i in range(1000000000):\n        pass\n"
+
+	tests := []struct {
+		name     string
+		maxSteps uint64
+		timeout  time.Duration
+	}{
+		{"max steps", 1000, 0},
+		{"timeout", 0, 50 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InitializeExecutionLimits(tt.maxSteps, tt.timeout)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				if _, err := RunTests("scope.star", "test(True).then(Include)", "scope_test.star", loop+"loop()\n"); err == nil {
+					t.Errorf("RunTests() expected error for endless loop in top level statements")
+				}
+				results, err := RunTests("scope.star", "test(True).then(Include)", "scope_test.star", loop+"def test_loop():\n    loop()\n")
+				if err != nil || len(results) != 1 || results[0].Passed() {
+					t.Errorf("RunTests() expected failure for endless loop in test function, got = %v, error = %v", results, err)
+				}
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("RunTests() did not stop test file with endless loop")
+			}
+		})
+	}
+}
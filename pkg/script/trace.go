@@ -0,0 +1,103 @@
+package script
+
+import (
+	"context"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+	"go.starlark.net/starlark"
+)
+
+const traceKey = "trace"
+
+// Trace is the structured record of an evaluation in explain mode.
+type Trace struct {
+	Entries []TraceEntry `json:"entries"`
+	// Decision is the index of the entry which set the final status, or -1 if no entry set it.
+	Decision int `json:"decision"`
+}
+
+// TraceEntry is one call to a matcher or to a function setting the status.
+type TraceEntry struct {
+	Function string       `json:"function"`
+	File     string       `json:"file"`
+	Line     int32        `json:"line"`
+	Col      int32        `json:"col"`
+	Args     []string     `json:"args,omitempty"`
+	Kwargs   []TraceValue `json:"kwargs,omitempty"`
+	Values   []TraceValue `json:"values,omitempty"`
+	Match    *bool        `json:"match,omitempty"`
+	Status   string       `json:"status,omitempty"`
+	Message  string       `json:"message,omitempty"`
+}
+
+// TraceValue is a named value in a trace entry.
+type TraceValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Explain evaluates the script like Run and returns a trace of the matchers called and the status they set.
+func (s *Script) Explain(ctx context.Context, qUri *frontier.QueuedUri, debug bool) (*scopechecker.ScopeCheckResponse, *Trace) {
+	trace := &Trace{Decision: -1}
+	result := s.run(ctx, qUri, debug, trace)
+
+	status := s.StatusName(result.ExcludeReason)
+	for i := len(trace.Entries) - 1; i >= 0; i-- {
+		if trace.Entries[i].Status != "" {
+			if trace.Entries[i].Status == status {
+				trace.Decision = i
+			}
+			break
+		}
+	}
+	return result, trace
+}
+
+// recordTrace adds a trace entry for a call to a builtin if the evaluation is traced. The values named 'match' and
+// 'status' are recorded as the result of the call, and msg is a message which is not a named value.
+func recordTrace(thread *starlark.Thread, funcName string, args starlark.Tuple, kwargs []starlark.Tuple, values []debugValue, msg string) {
+	trace, ok := thread.Local(traceKey).(*Trace)
+	if !ok {
+		return
+	}
+
+	entry := TraceEntry{Function: funcName, Message: msg}
+	if thread.CallStackDepth() > 1 {
+		pos := thread.CallFrame(1).Pos
+		entry.File = pos.Filename()
+		entry.Line = pos.Line
+		entry.Col = pos.Col
+	}
+	for _, a := range args {
+		entry.Args = append(entry.Args, argString(thread, a))
+	}
+	for _, kv := range kwargs {
+		entry.Kwargs = append(entry.Kwargs, TraceValue{Name: string(kv[0].(starlark.String)), Value: argString(thread, kv[1])})
+	}
+
+	for _, v := range values {
+		switch v.name {
+		case "match":
+			match := matchValue(v.value)
+			entry.Match = &match
+		case "status":
+			entry.Status = v.String()
+		default:
+			entry.Values = append(entry.Values, TraceValue{Name: v.name, Value: v.String()})
+		}
+	}
+	trace.Entries = append(trace.Entries, entry)
+}
+
+func matchValue(v interface{}) bool {
+	switch m := v.(type) {
+	case Match:
+		return bool(m)
+	case bool:
+		return m
+	case starlark.Value:
+		return bool(m.Truth())
+	}
+	return false
+}
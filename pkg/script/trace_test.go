@@ -0,0 +1,90 @@
+package script
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+)
+
+func TestScript_Explain(t *testing.T) {
+	src := "isScheme('http').otherwise(ChaffDetection)\n" +
+		"isSameHost().then(Include, continueEvaluation=True)\n" +
+		"maxHopsFromSeed(2).then(TooManyHops)\n"
+	yes, no := true, false
+
+	tests := []struct {
+		name         string
+		qUri         *frontier.QueuedUri
+		wantStatus   Status
+		wantEntries  []TraceEntry
+		wantDecision int
+	}{
+		{
+			name:       "too many hops",
+			qUri:       &frontier.QueuedUri{Uri: "http://a.com/x", SeedUri: "http://a.com/", DiscoveryPath: "LLL"},
+			wantStatus: TooManyHops,
+			wantEntries: []TraceEntry{
+				{Function: "isScheme", File: "explain", Line: 1, Col: 9, Args: []string{`"http"`},
+					Values: []TraceValue{{"scheme", "http"}, {"wantScheme", "http"}}, Match: &yes},
+				{Function: "isSameHost", File: "explain", Line: 2, Col: 11,
+					Values: []TraceValue{{"host", "a.com"}, {"seedHost", "a.com"}}, Match: &yes},
+				{Function: "match.then", File: "explain", Line: 2, Col: 18, Args: []string{"Include"},
+					Kwargs: []TraceValue{{"continueEvaluation", "True"}}, Status: "Include"},
+				{Function: "maxHopsFromSeed", File: "explain", Line: 3, Col: 16, Args: []string{"2"},
+					Values: []TraceValue{{"discoveryPath", "LLL"}, {"hops", "3"}}, Match: &yes},
+				{Function: "match.then", File: "explain", Line: 3, Col: 24, Args: []string{"TooManyHops"}, Status: "TooManyHops"},
+			},
+			wantDecision: 4,
+		},
+		{
+			name:       "blocked",
+			qUri:       &frontier.QueuedUri{Uri: "http://b.com/x", SeedUri: "http://a.com/"},
+			wantStatus: Blocked,
+			wantEntries: []TraceEntry{
+				{Function: "isScheme", File: "explain", Line: 1, Col: 9, Args: []string{`"http"`},
+					Values: []TraceValue{{"scheme", "http"}, {"wantScheme", "http"}}, Match: &yes},
+				{Function: "isSameHost", File: "explain", Line: 2, Col: 11,
+					Values: []TraceValue{{"host", "b.com"}, {"seedHost", "a.com"}}, Match: &no},
+				{Function: "maxHopsFromSeed", File: "explain", Line: 3, Col: 16, Args: []string{"2"},
+					Values: []TraceValue{{"discoveryPath", ""}, {"hops", "0"}}, Match: &no},
+			},
+			wantDecision: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, trace := CompileScopeScript("explain", src).Explain(context.Background(), tt.qUri, false)
+			if result.ExcludeReason != tt.wantStatus.AsInt32() {
+				t.Errorf("Explain().ExcludeReason got = %v, want %v", result.ExcludeReason, tt.wantStatus.AsInt32())
+			}
+			if result.Console != "" {
+				t.Errorf("Explain().Console got = %q, want no console output without debug", result.Console)
+			}
+			if !reflect.DeepEqual(trace.Entries, tt.wantEntries) {
+				t.Errorf("Explain() trace\ngot:  %+v\nwant: %+v", trace.Entries, tt.wantEntries)
+			}
+			if trace.Decision != tt.wantDecision {
+				t.Errorf("Explain().Decision got = %v, want %v", trace.Decision, tt.wantDecision)
+			}
+		})
+	}
+}
+
+func TestScript_ExplainValues(t *testing.T) {
+	// The values are recorded as computed, even when they contain the separators of the debug output
+	src := "isUrl('http://a.com/x?a=1, http://b.com/').then(Include)\n"
+	qUri := &frontier.QueuedUri{Uri: "http://b.com/", SeedUri: "http://a.com/"}
+	yes := true
+	want := []TraceEntry{
+		{Function: "isUrl", File: "values", Line: 1, Col: 6, Args: []string{`"http://a.com/x?a=1, http://b.com/"`},
+			Values: []TraceValue{{"test", "http://a.com/x?a=1, http://b.com/"}, {"url", "http://b.com/"}}, Match: &yes},
+		{Function: "match.then", File: "values", Line: 1, Col: 48, Args: []string{"Include"}, Status: "Include"},
+	}
+
+	_, trace := CompileScopeScript("values", src).Explain(context.Background(), qUri, false)
+	if !reflect.DeepEqual(trace.Entries, want) {
+		t.Errorf("Explain() trace\ngot:  %+v\nwant: %+v", trace.Entries, want)
+	}
+}
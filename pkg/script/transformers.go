@@ -1,11 +1,16 @@
 package script
 
 import (
+	"fmt"
+	"strings"
+
 	"go.starlark.net/starlark"
 )
 
 func init() {
 	starlark.Universe["removeQuery"] = starlark.NewBuiltin("removeQuery", removeQuery)
+	starlark.Universe["canonicalization"] = starlark.NewBuiltin("canonicalization", canonicalization)
+	starlark.Universe["stripParams"] = starlark.NewBuiltin("stripParams", stripParams)
 }
 
 func removeQuery(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -14,9 +19,67 @@ func removeQuery(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tup
 		return nil, err
 	}
 
-	qUrl := thread.Local(urlKey).(*UrlValue)
+	qUrl, err := transformUrl(thread)
+	if err != nil {
+		return nil, err
+	}
 
 	qUrl.parsedUri.SearchParams().Delete(q)
 
 	return starlark.None, nil
 }
+
+// canonicalization canonicalizes the url again from the queued uri with the named profile. It must be called before
+// the other transformers, since their changes would be lost.
+func canonicalization(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "profile", &name); err != nil {
+		return nil, err
+	}
+
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	if qUrl.frozen {
+		return nil, fmt.Errorf("cannot modify frozen url")
+	}
+	if qUrl.transformed {
+		return nil, fmt.Errorf("canonicalization() must be called before removeQuery() and stripParams()")
+	}
+
+	profile, err := CanonicalizationProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	u, err := profile.Parse(qUrl.qUri.Uri)
+	if err != nil {
+		return nil, IllegalUri.asError(err.Error())
+	}
+	qUrl = &UrlValue{qUri: qUrl.qUri, parsedUri: u, profile: profile}
+	thread.SetLocal(urlKey, qUrl)
+	printDebugValues(thread, b, args, kwargs, debugValue{"url", qUrl.String()})
+
+	return starlark.None, nil
+}
+
+// stripParams removes query and path parameters matching a space separated list of patterns.
+func stripParams(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var patterns string
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "patterns", &patterns); err != nil {
+		return nil, err
+	}
+
+	p, err := newParamPatterns(strings.Fields(patterns))
+	if err != nil {
+		return nil, err
+	}
+	qUrl, err := transformUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	p.strip(qUrl.parsedUri)
+	printDebugValues(thread, b, args, kwargs, debugValue{"url", qUrl.String()})
+
+	return starlark.None, nil
+}
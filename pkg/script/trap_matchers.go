@@ -0,0 +1,223 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+)
+
+func init() {
+	starlark.Universe["hasRepeatedPathSegments"] = starlark.NewBuiltin("hasRepeatedPathSegments", hasRepeatedPathSegments)
+	starlark.Universe["maxPathDepth"] = starlark.NewBuiltin("maxPathDepth", maxPathDepth)
+	starlark.Universe["maxUriLength"] = starlark.NewBuiltin("maxUriLength", maxUriLength)
+	starlark.Universe["maxQueryParams"] = starlark.NewBuiltin("maxQueryParams", maxQueryParams)
+	starlark.Universe["isCalendarLike"] = starlark.NewBuiltin("isCalendarLike", isCalendarLike)
+	starlark.Universe["looksLikeTrap"] = starlark.NewBuiltin("looksLikeTrap", looksLikeTrap)
+}
+
+// Default thresholds for looksLikeTrap.
+const (
+	defaultMaxRepeats     = 2
+	defaultMaxPathDepth   = 20
+	defaultMaxUriLength   = 2048
+	defaultMaxQueryParams = 20
+)
+
+// maxTrapSegments is the max number of path segments examined by repeatedPathSegments. The search is quadratic
+// or worse in the number of segments, and runs outside the step limit of the script.
+const maxTrapSegments = 256
+
+// trapHeuristic reports whether the url looks like a crawler trap. The details are the values found.
+type trapHeuristic func(u *UrlValue, threshold int) (match bool, details []debugValue)
+
+// repeatedPathSegments matches if a sequence of one or more path segments is repeated consecutively more than
+// maxRepeats times, e.g. '/a/b/a/b/a/b' repeats 'a/b' three times. Only the first maxTrapSegments segments are
+// examined.
+func repeatedPathSegments(u *UrlValue, maxRepeats int) (bool, []debugValue) {
+	segments := strings.Split(strings.Trim(u.parsedUri.Pathname(), "/"), "/")
+	n := len(segments)
+	if len(segments) > maxTrapSegments {
+		segments = segments[:maxTrapSegments]
+	}
+	// A sequence of l segments must fit maxRepeats+1 times
+	span := maxRepeats + 1
+	for l := 1; l*span <= len(segments); l++ {
+		for i := 0; i+l*span <= len(segments); i++ {
+			repeats := 1
+			for j := i + l; repeats <= maxRepeats && equalSegments(segments[i:i+l], segments[j:j+l]); j += l {
+				repeats++
+			}
+			if repeats > maxRepeats {
+				return true, []debugValue{{"segments", strings.Join(segments[i:i+l], "/")}, {"repeats", repeats}}
+			}
+		}
+	}
+	return false, []debugValue{{"segments", n}}
+}
+
+func equalSegments(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func pathDepth(u *UrlValue, maxDepth int) (bool, []debugValue) {
+	path := strings.Trim(u.parsedUri.Pathname(), "/")
+	depth := 0
+	if path != "" {
+		depth = strings.Count(path, "/") + 1
+	}
+	return depth > maxDepth, []debugValue{{"depth", depth}}
+}
+
+func uriLength(u *UrlValue, maxLength int) (bool, []debugValue) {
+	length := len(u.String())
+	return length > maxLength, []debugValue{{"length", length}}
+}
+
+func queryParams(u *UrlValue, maxParams int) (bool, []debugValue) {
+	count := 0
+	if u.parsedUri.Query() != "" {
+		count = strings.Count(u.parsedUri.Query(), "&") + 1
+	}
+	return count > maxParams, []debugValue{{"params", count}}
+}
+
+// datePattern matches dates like 2020-01-31, 2020/01/31, 2020.1 and 2020_01 in a path or query.
+var datePattern = regexp.MustCompile(`(?:^|[^0-9])((?:1[89]|2[0-9])[0-9]{2})[-/_.](?:0?[1-9]|1[0-2])(?:[-/_.](?:0?[1-9]|[12][0-9]|3[01]))?(?:[^0-9]|$)`)
+
+// yearParamPattern matches query parameters like year=2020.
+var yearParamPattern = regexp.MustCompile(`(?i)(?:^|&)(?:year|yr|y)=((?:1[89]|2[0-9])[0-9]{2})(?:&|$)`)
+
+// calendar matches urls containing a date. If maxYearsAway is positive, only dates with a year more than
+// maxYearsAway from the current year are matched.
+func calendar(u *UrlValue, maxYearsAway int) (bool, []debugValue) {
+	var year string
+	if m := datePattern.FindStringSubmatch(u.parsedUri.Pathname() + "?" + u.parsedUri.Query()); m != nil {
+		year = m[1]
+	} else if m := yearParamPattern.FindStringSubmatch(u.parsedUri.Query()); m != nil {
+		year = m[1]
+	}
+	if year == "" {
+		return false, []debugValue{{"year", starlark.None}}
+	}
+	if maxYearsAway <= 0 {
+		return true, []debugValue{{"year", year}}
+	}
+	y, _ := strconv.Atoi(year)
+	away := y - time.Now().Year()
+	if away < 0 {
+		away = -away
+	}
+	return away > maxYearsAway, []debugValue{{"year", year}, {"yearsAway", away}}
+}
+
+// runTrapHeuristic unpacks the threshold argument and runs the heuristic on the url. The threshold must be a
+// positive integer. None is only allowed if it is the default, and is then passed to the heuristic as zero.
+func runTrapHeuristic(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, name string, defaultThreshold starlark.Value, h trapHeuristic) (starlark.Value, error) {
+	threshold := defaultThreshold
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, name+"?", &threshold); err != nil {
+		return nil, err
+	}
+	t, err := parameterAsInt64(threshold)
+	switch {
+	case errors.Is(err, None) && defaultThreshold == starlark.None:
+	case errors.Is(err, None):
+		return nil, fmt.Errorf("%s must be a positive integer, got None", name)
+	case err != nil:
+		return nil, err
+	case t < 1:
+		return nil, fmt.Errorf("%s must be a positive integer, got %d", name, t)
+	}
+
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	match, details := h(qUrl, int(t))
+	printDebugValues(thread, b, args, kwargs, append(details, debugValue{"match", Match(match)})...)
+	return Match(match), nil
+}
+
+func hasRepeatedPathSegments(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return runTrapHeuristic(thread, b, args, kwargs, "maxRepeats", starlark.MakeInt(defaultMaxRepeats), repeatedPathSegments)
+}
+
+func maxPathDepth(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return runTrapHeuristic(thread, b, args, kwargs, "depth", starlark.MakeInt(defaultMaxPathDepth), pathDepth)
+}
+
+func maxUriLength(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return runTrapHeuristic(thread, b, args, kwargs, "length", starlark.MakeInt(defaultMaxUriLength), uriLength)
+}
+
+func maxQueryParams(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return runTrapHeuristic(thread, b, args, kwargs, "count", starlark.MakeInt(defaultMaxQueryParams), queryParams)
+}
+
+func isCalendarLike(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	return runTrapHeuristic(thread, b, args, kwargs, "maxYearsAway", starlark.None, calendar)
+}
+
+// looksLikeTrap runs all trap heuristics and matches if any of them matches. A threshold of None or zero
+// turns off the heuristic. The calendar heuristic is off unless maxYearsAway is given.
+func looksLikeTrap(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var maxRepeats starlark.Value = starlark.MakeInt(defaultMaxRepeats)
+	var maxDepth starlark.Value = starlark.MakeInt(defaultMaxPathDepth)
+	var maxLength starlark.Value = starlark.MakeInt(defaultMaxUriLength)
+	var maxParams starlark.Value = starlark.MakeInt(defaultMaxQueryParams)
+	var maxYearsAway starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs,
+		"maxRepeats?", &maxRepeats,
+		"maxDepth?", &maxDepth,
+		"maxLength?", &maxLength,
+		"maxQueryParams?", &maxParams,
+		"maxYearsAway?", &maxYearsAway); err != nil {
+		return nil, err
+	}
+
+	heuristics := []struct {
+		name      string
+		threshold starlark.Value
+		h         trapHeuristic
+	}{
+		{"repeatedPathSegments", maxRepeats, repeatedPathSegments},
+		{"pathDepth", maxDepth, pathDepth},
+		{"uriLength", maxLength, uriLength},
+		{"queryParams", maxParams, queryParams},
+		{"calendar", maxYearsAway, calendar},
+	}
+
+	qUrl, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range heuristics {
+		t, err := parameterAsInt64(h.threshold)
+		if err != nil {
+			if errors.Is(err, None) {
+				continue
+			}
+			return nil, err
+		}
+		if t <= 0 {
+			continue
+		}
+		if match, details := h.h(qUrl, int(t)); match {
+			values := append([]debugValue{{"heuristic", h.name}}, details...)
+			printDebugValues(thread, b, args, kwargs, append(values, debugValue{"match", True})...)
+			return True, nil
+		}
+	}
+
+	printDebugValues(thread, b, args, kwargs, debugValue{"match", False})
+	return False, nil
+}
@@ -0,0 +1,163 @@
+package script
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nlnwa/veidemann-api/go/commons/v1"
+	"github.com/nlnwa/veidemann-api/go/config/v1"
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+)
+
+// distinctSegments returns a path of n segments which are all different.
+func distinctSegments(n int) string {
+	segments := make([]string, n)
+	for i := range segments {
+		segments[i] = fmt.Sprint(i)
+	}
+	return strings.Join(segments, "/")
+}
+
+func Test_trapHeuristics(t *testing.T) {
+	thisYear := time.Now().Year()
+	tests := []struct {
+		name      string
+		heuristic trapHeuristic
+		uri       string
+		threshold int
+		want      bool
+	}{
+		{"repeated single segment", repeatedPathSegments, "http://a.com/x/a/a/a/y", 2, true},
+		{"repeated single segment below threshold", repeatedPathSegments, "http://a.com/x/a/a/a/y", 3, false},
+		{"repeated sequence", repeatedPathSegments, "http://a.com/a/b/a/b/a/b/c", 2, true},
+		{"no repeats", repeatedPathSegments, "http://a.com/a/b/c/a/b", 1, false},
+		{"root path", repeatedPathSegments, "http://a.com/", 1, false},
+		{"repeats at end of path", repeatedPathSegments, "http://a.com/x/y/a/b/a/b/a/b", 2, true},
+		{"many segments", repeatedPathSegments, "http://a.com/" + distinctSegments(2000), 1, false},
+		{"repeats after examined segments", repeatedPathSegments, "http://a.com/" + distinctSegments(maxTrapSegments) + "/a/a/a", 1, false},
+		{"path depth", pathDepth, "http://a.com/a/b/c/d", 3, true},
+		{"path depth at threshold", pathDepth, "http://a.com/a/b/c/", 3, false},
+		{"uri length", uriLength, "http://a.com/abcdefghij", 20, true},
+		{"uri length below threshold", uriLength, "http://a.com/abc", 20, false},
+		{"query params", queryParams, "http://a.com/?a=1&b=2&c=3", 2, true},
+		{"query params at threshold", queryParams, "http://a.com/?a=1&b=2", 2, false},
+		{"no query params", queryParams, "http://a.com/", 0, false},
+		{"calendar in path", calendar, "http://a.com/events/2019/05/12/", 0, true},
+		{"calendar in query", calendar, "http://a.com/cal?date=2019-05-12", 0, true},
+		{"calendar year param", calendar, "http://a.com/cal?month=5&year=2019", 0, true},
+		{"no calendar", calendar, "http://a.com/article/12345", 0, false},
+		{"calendar far future", calendar, fmt.Sprintf("http://a.com/cal/%d/01/", thisYear+10), 2, true},
+		{"calendar near", calendar, fmt.Sprintf("http://a.com/cal/%d/01/", thisYear+1), 2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := Url(&frontier.QueuedUri{Uri: tt.uri})
+			if err != nil {
+				t.Fatalf("Url() error = %v", err)
+			}
+			if got, detail := tt.heuristic(u, tt.threshold); got != tt.want {
+				t.Errorf("heuristic got = %v, want %v (%v)", got, tt.want, detail)
+			}
+		})
+	}
+}
+
+func Test_looksLikeTrap(t *testing.T) {
+	tests := []testdata{
+		{name: "looksLikeTrap1",
+			script: "looksLikeTrap(maxRepeats=3, maxDepth=None).then(TooManyHops)",
+			qUri: &frontier.QueuedUri{
+				Uri: "http://www.nb.no/a/a/a/a",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: TooManyHops.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://www.nb.no/a/a/a/a",
+					Scheme: "http",
+					Host:   "www.nb.no",
+					Port:   80,
+					Path:   "/a/a/a/a",
+				},
+				Console: "looksLikeTrap1:1:14 looksLikeTrap(maxRepeats=3, maxDepth=None) heuristic=repeatedPathSegments, segments=a, repeats=4, match=True\n" +
+					"looksLikeTrap1:1:48 match.then(TooManyHops) status=TooManyHops\n",
+			}},
+		{name: "looksLikeTrap2",
+			script: "looksLikeTrap(maxDepth=param('depth')).then(TooManyHops).otherwise(Include)",
+			qUri: &frontier.QueuedUri{
+				Uri:        "http://www.nb.no/a/b/c",
+				Annotation: []*config.Annotation{{Key: "depth", Value: "3"}},
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_INCLUDE,
+				ExcludeReason: Include.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://www.nb.no/a/b/c",
+					Scheme: "http",
+					Host:   "www.nb.no",
+					Port:   80,
+					Path:   "/a/b/c",
+				},
+				Console: "looksLikeTrap2:1:14 looksLikeTrap(maxDepth=\"3\") match=False\n" +
+					"looksLikeTrap2:1:67 match.otherwise(Include) status=Include\n",
+			}},
+		{name: "maxPathDepth1",
+			script: "maxPathDepth(2).then(TooManyHops)",
+			qUri: &frontier.QueuedUri{
+				Uri: "http://www.nb.no/a/b/c",
+			},
+			debug: true,
+			want: &scopechecker.ScopeCheckResponse{
+				Evaluation:    scopechecker.ScopeCheckResponse_EXCLUDE,
+				ExcludeReason: TooManyHops.AsInt32(),
+				IncludeCheckUri: &commons.ParsedUri{
+					Href:   "http://www.nb.no/a/b/c",
+					Scheme: "http",
+					Host:   "www.nb.no",
+					Port:   80,
+					Path:   "/a/b/c",
+				},
+				Console: "maxPathDepth1:1:13 maxPathDepth(2) depth=3, match=True\n" +
+					"maxPathDepth1:1:21 match.then(TooManyHops) status=TooManyHops\n",
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, tt.qUri, tt.debug)
+			verify(t, got, tt.want)
+		})
+	}
+}
+
+func Test_trapThresholds(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   Status
+	}{
+		{"depth none", "maxPathDepth(None).then(Blocked)", RuntimeException},
+		{"depth zero", "maxPathDepth(0).then(Blocked)", RuntimeException},
+		{"repeats negative", "hasRepeatedPathSegments(-1).then(Blocked)", RuntimeException},
+		{"length zero", "maxUriLength(length=0).then(Blocked)", RuntimeException},
+		{"params none", "maxQueryParams(None).then(Blocked)", RuntimeException},
+		{"calendar zero", "isCalendarLike(0).then(Blocked)", RuntimeException},
+		{"calendar none", "isCalendarLike(None).then(Blocked)", Blocked},
+		{"calendar default", "isCalendarLike().then(Blocked)", Blocked},
+		{"trap zero is off", "looksLikeTrap(maxDepth=0).then(Blocked)", Include},
+		{"trap none is off", "looksLikeTrap(maxDepth=None).then(Blocked)", Include},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qUri := &frontier.QueuedUri{Uri: "http://www.nb.no/2019/05/12/a"}
+			got := RunScopeScript(tt.name, tt.script+"\ntest(True).then(Include)", qUri, false)
+			if got.ExcludeReason != tt.want.AsInt32() {
+				t.Errorf("RunScopeScript().ExcludeReason got = %v, want %v, error: %v", got.ExcludeReason, tt.want.AsInt32(), got.Error)
+			}
+		})
+	}
+}
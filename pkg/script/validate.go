@@ -0,0 +1,275 @@
+package script
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// Severity is the severity of a diagnostic.
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// MarshalText marshals the severity as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Diagnostic is a problem found when validating a script.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Line     int32    `json:"line"`
+	Col      int32    `json:"col"`
+	Message  string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.File, d.Line, d.Col, d.Severity, d.Message)
+}
+
+// HasErrors returns true if any of the diagnostics is an error.
+func HasErrors(diagnostics []Diagnostic) bool {
+	for _, d := range diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// sideEffectBuiltins are the builtins which are called for their side effect rather than their result. The
+// transformers change the url, and abort stops the evaluation.
+var sideEffectBuiltins = map[string]bool{
+	"setStatus":        true,
+	"debug":            true,
+	"print":            true,
+	"fail":             true,
+	"abort":            true,
+	"removeQuery":      true,
+	"stripParams":      true,
+	"canonicalization": true,
+}
+
+// checkedArgs are the names of the arguments checked by the validator for each function.
+var checkedArgs = map[string]string{
+	"then":      "status",
+	"otherwise": "status",
+	"setStatus": "status",
+	"param":     "name",
+}
+
+// ValidateScript checks a scope script without evaluating it. The script is compiled like in RunScopeScript, and
+// the diagnostics report syntax errors, undefined names, unknown statuses and results of matchers which are not
+// used. If annotations is not nil, calls to param() with a name which is not in annotations are reported as well.
+// Statuses declared in the script header are validated like when the script is compiled.
+func ValidateScript(name string, src interface{}, annotations []string) []Diagnostic {
+	v := &validator{name: name, statuses: make(map[string]bool)}
+
+	decls, err := scanStatusHeader(name, src)
+	if err != nil {
+		v.addError(err)
+		return v.diagnostics
+	}
+	declared := starlark.StringDict{}
+	for _, d := range decls {
+		if err := checkHeaderStatus(declared, d); err != nil {
+			v.add(SeverityError, d.pos, err.Error())
+			continue
+		}
+		declared[d.name] = d.status
+		v.statuses[d.name] = true
+	}
+
+	f, err := fileOptions.Parse(name, src, 0)
+	if err != nil {
+		v.addError(err)
+		return v.diagnostics
+	}
+
+	isPredeclared := func(name string) bool {
+		return v.statuses[name]
+	}
+	// Resolving binds the identifiers, which is used by checkCalls
+	resolveErr := resolve.File(f, isPredeclared, starlark.Universe.Has)
+	v.checkCalls(f, annotations)
+	if err := resolveErr; err != nil {
+		var errs resolve.ErrorList
+		if errors.As(err, &errs) {
+			for _, e := range errs {
+				if !v.reported[e.Pos] {
+					v.add(SeverityError, e.Pos, e.Msg)
+				}
+			}
+		} else {
+			v.addError(err)
+		}
+	}
+
+	sort.SliceStable(v.diagnostics, func(i, j int) bool {
+		a, b := v.diagnostics[i], v.diagnostics[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Col < b.Col
+	})
+	return v.diagnostics
+}
+
+type validator struct {
+	name        string
+	statuses    map[string]bool // statuses declared in the script header
+	diagnostics []Diagnostic
+	reported    map[syntax.Position]bool // positions of identifiers already reported as unknown statuses
+}
+
+func (v *validator) add(severity Severity, pos syntax.Position, msg string) {
+	v.diagnostics = append(v.diagnostics, Diagnostic{
+		Severity: severity,
+		File:     pos.Filename(),
+		Line:     pos.Line,
+		Col:      pos.Col,
+		Message:  msg,
+	})
+}
+
+func (v *validator) addError(err error) {
+	var syntaxErr syntax.Error
+	if errors.As(err, &syntaxErr) {
+		v.add(SeverityError, syntaxErr.Pos, syntaxErr.Msg)
+		return
+	}
+	v.diagnostics = append(v.diagnostics, Diagnostic{Severity: SeverityError, File: v.name, Message: err.Error()})
+}
+
+func (v *validator) isStatus(name string) bool {
+	if v.statuses[name] {
+		return true
+	}
+	_, ok := StatusByName(name)
+	return ok
+}
+
+// checkCalls checks the statuses given to then(), otherwise() and setStatus(), the names given to param() and
+// that the results of builtins are used.
+func (v *validator) checkCalls(f *syntax.File, annotations []string) {
+	var annotationSet map[string]bool
+	if annotations != nil {
+		annotationSet = make(map[string]bool, len(annotations))
+		for _, a := range annotations {
+			annotationSet[a] = true
+		}
+	}
+
+	syntax.Walk(f, func(n syntax.Node) bool {
+		if e, ok := n.(*syntax.ExprStmt); ok {
+			v.checkResultUsed(e)
+			return true
+		}
+		call, ok := n.(*syntax.CallExpr)
+		if !ok {
+			return true
+		}
+		var fn string
+		switch c := call.Fn.(type) {
+		case *syntax.Ident:
+			fn = c.Name
+		case *syntax.DotExpr:
+			fn = c.Name.Name
+		}
+		arg := firstArg(call, checkedArgs[fn])
+		if arg == nil {
+			return true
+		}
+
+		switch fn {
+		case "then", "otherwise", "setStatus":
+			v.checkStatusArg(fn, arg)
+		case "param":
+			if lit, ok := arg.(*syntax.Literal); ok && lit.Token == syntax.STRING && annotationSet != nil {
+				if name := lit.Value.(string); !annotationSet[name] {
+					v.add(SeverityWarning, lit.TokenPos, fmt.Sprintf("param '%s' is not in the annotations", name))
+				}
+			}
+		}
+		return true
+	})
+}
+
+// checkResultUsed reports a statement which calls a builtin and discards the result, e.g. 'isSameHost()' without
+// then() or otherwise().
+func (v *validator) checkResultUsed(stmt *syntax.ExprStmt) {
+	call, ok := stmt.X.(*syntax.CallExpr)
+	if !ok {
+		return
+	}
+	id, ok := call.Fn.(*syntax.Ident)
+	if !ok || sideEffectBuiltins[id.Name] {
+		return
+	}
+	if b, ok := id.Binding.(*resolve.Binding); ok && b.Scope == resolve.Universal {
+		v.add(SeverityWarning, id.NamePos,
+			fmt.Sprintf("result of %s() is not used, use then() or otherwise() to set a status", id.Name))
+	}
+}
+
+// checkStatusArg reports a status argument which is not a known status.
+func (v *validator) checkStatusArg(fn string, arg syntax.Expr) {
+	switch a := arg.(type) {
+	case *syntax.Ident:
+		if b, ok := a.Binding.(*resolve.Binding); ok && b.Scope != resolve.Universal && b.Scope != resolve.Predeclared && b.Scope != resolve.Undefined {
+			// A variable defined in the script
+			return
+		}
+		if v.isStatus(a.Name) {
+			return
+		}
+		if v.reported == nil {
+			v.reported = make(map[syntax.Position]bool)
+		}
+		v.reported[a.NamePos] = true
+		if starlark.Universe.Has(a.Name) {
+			v.add(SeverityError, a.NamePos, fmt.Sprintf("%s is not a status in %s()", a.Name, fn))
+		} else {
+			v.add(SeverityError, a.NamePos, fmt.Sprintf("unknown status %s in %s()", a.Name, fn))
+		}
+	case *syntax.Literal:
+		if name, ok := a.Value.(string); ok && !v.isStatus(name) {
+			v.add(SeverityError, a.TokenPos, fmt.Sprintf("unknown status '%s' in %s()", name, fn))
+		}
+	}
+}
+
+// firstArg returns the first positional argument of a call, or the keyword argument with the given name.
+func firstArg(call *syntax.CallExpr, name string) syntax.Expr {
+	if name == "" {
+		return nil
+	}
+	for i, a := range call.Args {
+		if b, ok := a.(*syntax.BinaryExpr); ok && b.Op == syntax.EQ {
+			if id, ok := b.X.(*syntax.Ident); ok && id.Name == name {
+				return b.Y
+			}
+			continue
+		}
+		if i == 0 {
+			return a
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateScript(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		annotations []string
+		want        []string
+	}{
+		{"valid", "isScheme('http').otherwise(Blocked)\ntest(True).then(Include)", nil, nil},
+		{"syntax error", "test(", nil,
+			[]string{"syntax error:1:6: error: got end of file, want ')'"}},
+		{"undefined builtin", "isSchem('http').otherwise(Blocked)", nil,
+			[]string{"undefined builtin:1:1: error: undefined: isSchem"}},
+		{"unknown status", "isScheme('http').otherwise(Blockd)\nsetStatus('Foo')", nil,
+			[]string{
+				"unknown status:1:28: error: unknown status Blockd in otherwise()",
+				"unknown status:2:11: error: unknown status 'Foo' in setStatus()",
+			}},
+		{"not a status", "isScheme('http').then(status=isSameHost)", nil,
+			[]string{"not a status:1:30: error: isSameHost is not a status in then()"}},
+		{"status variable", "s = Blocked\nisScheme('http').then(s)", nil, nil},
+		{"transformers", "canonicalization('crawl')\nremoveQuery('utm_source')\nstripParams('jsessionid')\nisScheme('http').then(Include)", nil, nil},
+		{"abort", "isScheme('ftp').then(Blocked, continueEvaluation=True)\nabort()", nil, nil},
+		{"unused result", "isSameHost()\ndef f():\n    maxHopsFromSeed(2)\n    setStatus(Blocked)\nf()", nil,
+			[]string{
+				"unused result:1:1: warning: result of isSameHost() is not used, use then() or otherwise() to set a status",
+				"unused result:3:5: warning: result of maxHopsFromSeed() is not used, use then() or otherwise() to set a status",
+			}},
+		{"param in annotations", "maxHopsFromSeed(param('hops')).then(TooManyHops)", []string{"hops"}, nil},
+		{"param not in annotations", "maxHopsFromSeed(param('hops')).then(TooManyHops)\nparam(name='other')", []string{"scope_hops"},
+			[]string{
+				"param not in annotations:1:23: warning: param 'hops' is not in the annotations",
+				"param not in annotations:2:1: warning: result of param() is not used, use then() or otherwise() to set a status",
+				"param not in annotations:2:12: warning: param 'other' is not in the annotations",
+			}},
+		{"param not checked", "maxHopsFromSeed(param('hops')).then(TooManyHops)", nil, nil},
+		{"header status", "# status: ValidateHeaderStatus = -5090\ntest(True).then(ValidateHeaderStatus)", nil, nil},
+		{"illegal header status", "# status: Blocked = -5091\ntest(True).then(Blocked)", nil,
+			[]string{"illegal header status:1:1: error: status Blocked is already registered with code -5001"}},
+		{"header status declared twice", "# status: Twice = -5090\n# status: Twice = -5091\ntest(True).then(Twice)", nil,
+			[]string{"header status declared twice:2:1: error: status Twice is already declared with code -5090"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range ValidateScript(tt.name, tt.script, tt.annotations) {
+				got = append(got, d.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ValidateScript() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := StatusByName("ValidateHeaderStatus"); ok {
+		t.Errorf("ValidateScript() registered status from script header")
+	}
+}
@@ -1,6 +1,7 @@
 package script
 
 import (
+	"fmt"
 	"github.com/nlnwa/veidemann-api/go/commons/v1"
 	"github.com/nlnwa/veidemann-api/go/frontier/v1"
 	"github.com/nlnwa/whatwg-url/url"
@@ -12,17 +13,27 @@ import (
 type UrlValue struct {
 	qUri      *frontier.QueuedUri
 	parsedUri *url.Url
+	profile   url.Parser // the canonicalization profile used for parsedUri
+	frozen    bool
+	// transformed is true if removeQuery or stripParams changed the url after it was canonicalized
+	transformed bool
 }
 
 func Url(u *frontier.QueuedUri) (*UrlValue, error) {
 	r := &UrlValue{
-		qUri: u,
+		qUri:    u,
+		profile: ScopeCanonicalizationProfile,
 	}
 	var err error
-	r.parsedUri, err = ScopeCanonicalizationProfile.Parse(u.Uri)
+	r.parsedUri, err = r.profile.Parse(u.Uri)
 	return r, err
 }
 
+// canonicalize parses rawUrl with the same canonicalization profile as the url, making them comparable.
+func (u *UrlValue) canonicalize(rawUrl string) (*url.Url, error) {
+	return u.profile.Parse(rawUrl)
+}
+
 func (u *UrlValue) String() string {
 	if u.parsedUri == nil {
 		return u.qUri.Uri
@@ -51,29 +62,123 @@ func (u *UrlValue) Type() string {
 	return "url"
 }
 
+// Freeze makes the url immutable. Transformers fail on a frozen url.
 func (u *UrlValue) Freeze() {
-	panic("implement me")
+	u.frozen = true
 }
 
 func (u *UrlValue) Truth() starlark.Bool {
-	panic("implement me")
+	return true
 }
 
+// Hash hashes the canonicalized form of the url. Transformers replace the url instead of modifying it, so a url used
+// as a key in a dict or set does not change.
 func (u *UrlValue) Hash() (uint32, error) {
-	panic("implement me")
+	return starlark.String(u.String()).Hash()
+}
+
+// transformUrl returns a copy of the current url of the thread for a transformer to modify, and makes the copy the
+// current url.
+func transformUrl(thread *starlark.Thread) (*UrlValue, error) {
+	u, err := threadUrl(thread)
+	if err != nil {
+		return nil, err
+	}
+	if u.frozen {
+		return nil, fmt.Errorf("cannot modify frozen url")
+	}
+	parsed, err := u.profile.Parse(u.parsedUri.String())
+	if err != nil {
+		return nil, IllegalUri.asError(err.Error())
+	}
+	c := &UrlValue{qUri: u.qUri, parsedUri: parsed, profile: u.profile, transformed: true}
+	thread.SetLocal(urlKey, c)
+	return c, nil
+}
+
+// CompareSameType compares urls by their canonicalized form.
+func (u *UrlValue) CompareSameType(op syntax.Token, y_ starlark.Value, _ int) (bool, error) {
+	y := y_.(*UrlValue)
+	switch op {
+	case syntax.EQL:
+		return u.String() == y.String(), nil
+	case syntax.NEQ:
+		return u.String() != y.String(), nil
+	default:
+		return false, fmt.Errorf("%v %v %v not implemented", u.Type(), op, y.Type())
+	}
 }
 
 func (u *UrlValue) Attr(name string) (starlark.Value, error) {
+	if a, ok := urlAttrs[name]; ok {
+		return a(u), nil
+	}
 	return builtinAttr(u, name, urlMethods)
 }
 
 func (u *UrlValue) AttrNames() []string {
-	return builtinAttrNames(urlMethods)
+	names := builtinAttrNames(urlMethods)
+	for name := range urlAttrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// urlAttrs are the attributes of a url. Components of the url are taken from the canonicalized url while the
+// rest are taken from the queued uri.
+var urlAttrs = map[string]func(u *UrlValue) starlark.Value{
+	"href":           func(u *UrlValue) starlark.Value { return starlark.String(u.String()) },
+	"scheme":         func(u *UrlValue) starlark.Value { return starlark.String(u.parsedUri.Scheme()) },
+	"hostname":       func(u *UrlValue) starlark.Value { return starlark.String(u.parsedUri.Hostname()) },
+	"path":           func(u *UrlValue) starlark.Value { return starlark.String(u.parsedUri.Pathname()) },
+	"query":          func(u *UrlValue) starlark.Value { return starlark.String(u.parsedUri.Query()) },
+	"fragment":       func(u *UrlValue) starlark.Value { return starlark.String(u.parsedUri.Fragment()) },
+	"searchParams":   uriSearchParams,
+	"discoveryPath":  func(u *UrlValue) starlark.Value { return starlark.String(u.qUri.DiscoveryPath) },
+	"referrer":       func(u *UrlValue) starlark.Value { return starlark.String(u.qUri.Referrer) },
+	"seed":           func(u *UrlValue) starlark.Value { return starlark.String(u.qUri.SeedUri) },
+	"ip":             func(u *UrlValue) starlark.Value { return starlark.String(u.qUri.Ip) },
+	"retries":        func(u *UrlValue) starlark.Value { return starlark.MakeInt(int(u.qUri.Retries)) },
+	"executionId":    func(u *UrlValue) starlark.Value { return starlark.String(u.qUri.ExecutionId) },
+	"jobExecutionId": func(u *UrlValue) starlark.Value { return starlark.String(u.qUri.JobExecutionId) },
+	"priorityWeight": func(u *UrlValue) starlark.Value { return starlark.Float(u.qUri.PriorityWeight) },
 }
 
 var urlMethods = map[string]*starlark.Builtin{
-	"host": starlark.NewBuiltin("host", uriGetHost),
-	"port": starlark.NewBuiltin("port", uriGetPort),
+	"host":       starlark.NewBuiltin("host", uriGetHost),
+	"port":       starlark.NewBuiltin("port", uriGetPort),
+	"surt":       starlark.NewBuiltin("surt", uriGetSurt),
+	"queryParam": starlark.NewBuiltin("queryParam", uriGetQueryParam),
+}
+
+// uriSearchParams returns a frozen dict mapping each query parameter name to a list of its values.
+func uriSearchParams(u *UrlValue) starlark.Value {
+	d := new(starlark.Dict)
+	u.parsedUri.SearchParams().Iterate(func(pair *url.NameValuePair) {
+		values, found, _ := d.Get(starlark.String(pair.Name))
+		if !found {
+			values = new(starlark.List)
+			_ = d.SetKey(starlark.String(pair.Name), values)
+		}
+		_ = values.(*starlark.List).Append(starlark.String(pair.Value))
+	})
+	d.Freeze()
+	return d
+}
+
+func uriGetQueryParam(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var def starlark.Value = starlark.None
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "name", &name, "default?", &def); err != nil {
+		return nil, err
+	}
+
+	u := b.Receiver().(*UrlValue)
+	if !u.parsedUri.SearchParams().Has(name) {
+		return def, nil
+	}
+	return starlark.String(u.parsedUri.SearchParams().Get(name)), nil
 }
 
 func uriGetHost(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -94,6 +199,15 @@ func uriGetPort(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kw
 	return starlark.String(u.parsedUri.Port()), nil
 }
 
+func uriGetSurt(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 0); err != nil {
+		return nil, err
+	}
+
+	u := b.Receiver().(*UrlValue)
+	return starlark.String(u.Surt()), nil
+}
+
 var (
 	True  Match = true
 	False Match = false
@@ -150,15 +264,15 @@ func otherwise(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple
 }
 
 func matchAction(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple, invert bool) (starlark.Value, error) {
-	var status Status
+	status := statusArg{thread: thread}
 	var continueEvaluation = starlark.False
 	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "status", &status, "continueEvaluation?", &continueEvaluation); err != nil {
 		return nil, err
 	}
 	match, _ := b.Receiver().(Match)
 	if bool(match) != invert {
-		printDebug(thread, b, args, kwargs, "status="+status.String())
-		thread.SetLocal(resultKey, status)
+		printDebugValues(thread, b, args, kwargs, debugValue{"status", statusName(thread, status.status)})
+		thread.SetLocal(resultKey, status.status)
 		if continueEvaluation {
 			return match, nil
 		} else {
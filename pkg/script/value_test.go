@@ -0,0 +1,99 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+	"go.starlark.net/starlark"
+)
+
+func TestUrlValue_Attr(t *testing.T) {
+	qUri := &frontier.QueuedUri{
+		Uri:            "https://foo.bar:8443/aa/bb?id=1&b=2&b=3#frag",
+		SeedUri:        "https://foo.bar/",
+		Referrer:       "https://foo.bar/aa",
+		DiscoveryPath:  "LLE",
+		Ip:             "10.0.0.1",
+		Retries:        2,
+		ExecutionId:    "eid1",
+		JobExecutionId: "jeid1",
+		PriorityWeight: 1.5,
+	}
+
+	tests := []struct {
+		name string
+		test string
+	}{
+		{"href", "url().href == 'https://foo.bar:8443/aa/bb?b=2&b=3&id=1'"},
+		{"scheme", "url().scheme == 'https'"},
+		{"hostname", "url().hostname == 'foo.bar'"},
+		{"host", "url().host() == 'foo.bar:8443'"},
+		{"port", "url().port() == '8443'"},
+		{"path", "url().path == '/aa/bb'"},
+		{"query", "url().query == 'b=2&b=3&id=1'"},
+		{"fragment", "url().fragment == ''"},
+		{"searchParams", "url().searchParams == {'b': ['2', '3'], 'id': ['1']}"},
+		{"queryParam", "url().queryParam('id') == '1'"},
+		{"queryParamFirst", "url().queryParam('b') == '2'"},
+		{"queryParamMissing", "url().queryParam('foo') == None"},
+		{"queryParamDefault", "url().queryParam('foo', default='x') == 'x'"},
+		{"discoveryPath", "url().discoveryPath == 'LLE'"},
+		{"referrer", "url().referrer == 'https://foo.bar/aa'"},
+		{"seed", "url().seed == 'https://foo.bar/'"},
+		{"ip", "url().ip == '10.0.0.1'"},
+		{"retries", "url().retries == 2"},
+		{"executionId", "url().executionId == 'eid1'"},
+		{"jobExecutionId", "url().jobExecutionId == 'jeid1'"},
+		{"priorityWeight", "url().priorityWeight == 1.5"},
+		{"truth", "bool(url())"},
+		{"dictKey", "{url(): 1}[url()] == 1"},
+		{"equal", "url() == url()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, "test("+tt.test+").then(Include)", qUri, false)
+			if got.Evaluation != scopechecker.ScopeCheckResponse_INCLUDE {
+				t.Errorf("%v got = %v, want %v, error: %v", tt.test, got.Evaluation, scopechecker.ScopeCheckResponse_INCLUDE, got.Error)
+			}
+		})
+	}
+}
+
+func TestUrlValue_Freeze(t *testing.T) {
+	qUri := &frontier.QueuedUri{Uri: "http://foo.bar/?a=1"}
+	u, err := Url(qUri)
+	if err != nil {
+		t.Fatalf("Url() error = %v", err)
+	}
+	u.Freeze()
+	thread := &starlark.Thread{}
+	thread.SetLocal(urlKey, u)
+	if _, err := starlark.Call(thread, starlark.Universe["removeQuery"], starlark.Tuple{starlark.String("a")}, nil); err == nil {
+		t.Errorf("removeQuery() on frozen url expected error")
+	}
+	if u.String() != "http://foo.bar/?a=1" {
+		t.Errorf("frozen url was modified, got = %v", u.String())
+	}
+}
+
+func TestUrlValue_Hash(t *testing.T) {
+	qUri := &frontier.QueuedUri{Uri: "http://foo.bar/?a=1&b=2"}
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"removeQuery", "d = {url(): 1}\nremoveQuery('a')\nstripParams('b')\n" +
+			"test(url() not in d and str(d.keys()[0]) == 'http://foo.bar/?a=1&b=2' and str(url()) == 'http://foo.bar/').then(Include)"},
+		{"canonicalization", "s = set([url()])\ncanonicalization('crawl')\ntest(len(s) == 1 and str(list(s)[0]) == 'http://foo.bar/?a=1&b=2').then(Include)"},
+		{"same url", "u = url()\nd = {u: 1}\nremoveQuery('c')\ntest(u in d and url() in d and u == url()).then(Include)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RunScopeScript(tt.name, tt.script, qUri, false)
+			if got.Evaluation != scopechecker.ScopeCheckResponse_INCLUDE {
+				t.Errorf("transformer after hashing url got = %v, %v, want %v", got.ExcludeReason, got.Error, scopechecker.ScopeCheckResponse_INCLUDE)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package script
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// configMapDataDir is the symlink which Kubernetes swaps to update all files of a mounted ConfigMap or Secret at
+// once. The files in the directory are symlinks through it, so the swap gives no events for the files themselves.
+const configMapDataDir = "..data"
+
+// reloadDelay is the time to wait for more changes to a file before it is reloaded. Files are often written
+// in several steps, e.g. truncated before the new content is written.
+const reloadDelay = 100 * time.Millisecond
+
+// dirWatcher calls reload for the files in a directory when they are created, changed or removed. All files are
+// reloaded when the data of a Kubernetes ConfigMap or Secret mounted as the directory is updated.
+type dirWatcher struct {
+	dir     string
+	match   func(file string) bool
+	reload  func(file string)
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+
+	reloadMu sync.Mutex
+	timers   map[string]*time.Timer
+}
+
+// watchDir calls reload for every file in dir accepted by match, then watches dir and calls reload again for
+// each file which changes. Reload must handle files which no longer exist.
+func watchDir(dir string, match func(file string) bool, reload func(file string)) (*dirWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(dir); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+	w := &dirWatcher{
+		dir:     dir,
+		match:   match,
+		reload:  reload,
+		watcher: watcher,
+		done:    make(chan struct{}),
+		timers:  make(map[string]*time.Timer),
+	}
+
+	if err := w.scan(); err != nil {
+		_ = watcher.Close()
+		return nil, err
+	}
+
+	go w.watch()
+	return w, nil
+}
+
+// scan calls reload for every file in the directory accepted by match.
+func (w *dirWatcher) scan() error {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if f := filepath.Join(w.dir, e.Name()); !e.IsDir() && w.match(f) {
+			w.reload(f)
+		}
+	}
+	return nil
+}
+
+func (w *dirWatcher) watch() {
+	defer close(w.done)
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			switch {
+			case filepath.Base(event.Name) == configMapDataDir:
+				w.schedule(w.dir, func() {
+					if err := w.scan(); err != nil {
+						log.Error().Err(err).Msgf("Failed to scan %s", w.dir)
+					}
+				})
+			case w.match(event.Name):
+				file := event.Name
+				w.schedule(file, func() { w.reload(file) })
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Error().Err(err).Msgf("Error watching %s", w.dir)
+		}
+	}
+}
+
+// schedule calls f when there have been no events for key, a file or the whole directory, for reloadDelay.
+func (w *dirWatcher) schedule(key string, f func()) {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+	if t, ok := w.timers[key]; ok {
+		t.Reset(reloadDelay)
+		return
+	}
+	w.timers[key] = time.AfterFunc(reloadDelay, func() {
+		w.reloadMu.Lock()
+		delete(w.timers, key)
+		w.reloadMu.Unlock()
+		f()
+	})
+}
+
+// close stops watching the directory and cancels pending reloads.
+func (w *dirWatcher) close() {
+	_ = w.watcher.Close()
+	<-w.done
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+	for _, t := range w.timers {
+		t.Stop()
+	}
+}
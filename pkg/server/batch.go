@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	"veidemann-scopeservice/api/scopeservice/v1"
+	"veidemann-scopeservice/pkg/script"
+
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+)
+
+type ScopeCheckerBatchService struct {
+	scopeservice.UnimplementedScopeCheckerBatchServiceServer
+	workers int
+}
+
+// NewScopeCheckerBatchService returns a batch service which evaluates each batch on the given number of workers.
+func NewScopeCheckerBatchService(workers int) *ScopeCheckerBatchService {
+	if workers < 1 {
+		workers = 1
+	}
+	return &ScopeCheckerBatchService{workers: workers}
+}
+
+func (s *ScopeCheckerBatchService) ScopeCheckBatch(ctx context.Context, request *scopeservice.ScopeCheckBatchRequest) (*scopeservice.ScopeCheckBatchResponse, error) {
+	compiled := script.CompileScopeScript(request.ScopeScriptName, request.ScopeScript)
+	responses := make([]*scopechecker.ScopeCheckResponse, len(request.QueuedUri))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var err error
+	for w := 0; w < s.workers && w < len(request.QueuedUri); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r, e := runScopeCheck(ctx, compiled, request.QueuedUri[i], request.Debug)
+				if e != nil {
+					errOnce.Do(func() { err = e })
+					continue
+				}
+				responses[i] = r
+			}
+		}()
+	}
+feed:
+	for i := range request.QueuedUri {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return nil, contextError(err)
+	}
+	return &scopeservice.ScopeCheckBatchResponse{Response: responses}, nil
+}
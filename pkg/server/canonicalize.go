@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"veidemann-scopeservice/api/scopeservice/v1"
+	"veidemann-scopeservice/pkg/script"
+	"veidemann-scopeservice/pkg/telemetry"
+
+	"github.com/nlnwa/whatwg-url/url"
+	"golang.org/x/net/idna"
+	"google.golang.org/grpc"
+)
+
+type UriCanonicalizerBatchService struct {
+	scopeservice.UnimplementedUriCanonicalizerBatchServiceServer
+}
+
+// CanonicalizeStream canonicalizes each URI received on the stream. Like Canonicalize, a named profile can be
+// selected with CanonicalizationProfileKey in the metadata of the call.
+func (s *UriCanonicalizerBatchService) CanonicalizeStream(stream grpc.BidiStreamingServer[scopeservice.CanonicalizeStreamRequest, scopeservice.CanonicalizeStreamResponse]) error {
+	profile, err := canonicalizationProfile(stream.Context())
+	if err != nil {
+		return err
+	}
+	for {
+		request, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		telemetry.CanonicalizationsTotal.Inc()
+		if err := stream.Send(canonicalizeRepresentations(profile, request.Uri)); err != nil {
+			return err
+		}
+	}
+}
+
+// canonicalizeRepresentations returns the canonical form of uri with its SURT form, host forms and hash.
+func canonicalizeRepresentations(profile url.Parser, uri string) *scopeservice.CanonicalizeStreamResponse {
+	response := &scopeservice.CanonicalizeStreamResponse{Uri: uri}
+	canonicalized, err := profile.Parse(uri)
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+
+	response.CanonicalUri = asParsedUri(canonicalized)
+	response.Surt = script.Surt(canonicalized)
+	// The parser converts internationalized hosts to punycode
+	response.HostAscii = canonicalized.Hostname()
+	response.HostUnicode = response.HostAscii
+	if u, err := idna.ToUnicode(response.HostAscii); err == nil {
+		response.HostUnicode = u
+	}
+	hash := sha256.Sum256([]byte(response.CanonicalUri.Href))
+	response.Hash = hex.EncodeToString(hash[:])
+	return response
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+
+	"veidemann-scopeservice/api/scopeservice/v1"
+	"veidemann-scopeservice/pkg/script"
+
+	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+)
+
+type ScopeExplainService struct {
+	scopeservice.UnimplementedScopeExplainServiceServer
+}
+
+func (s *ScopeExplainService) Explain(ctx context.Context, request *scopechecker.ScopeCheckRequest) (*scopeservice.ExplainResponse, error) {
+	compiled := script.CompileScopeScript(request.ScopeScriptName, request.ScopeScript)
+	result, trace := compiled.Explain(ctx, request.QueuedUri, request.Debug)
+	if err := ctx.Err(); err != nil {
+		return nil, contextError(err)
+	}
+
+	response := &scopeservice.ExplainResponse{
+		Response: result,
+		Decision: int32(trace.Decision),
+	}
+	for _, e := range trace.Entries {
+		response.Trace = append(response.Trace, &scopeservice.TraceEntry{
+			Function: e.Function,
+			File:     e.File,
+			Line:     e.Line,
+			Col:      e.Col,
+			Args:     e.Args,
+			Kwargs:   traceValues(e.Kwargs),
+			Values:   traceValues(e.Values),
+			Match:    e.Match,
+			Status:   e.Status,
+			Message:  e.Message,
+		})
+	}
+	return response, nil
+}
+
+func traceValues(values []script.TraceValue) []*scopeservice.TraceValue {
+	var v []*scopeservice.TraceValue
+	for _, tv := range values {
+		v = append(v, &scopeservice.TraceValue{Name: tv.Name, Value: tv.Value})
+	}
+	return v
+}
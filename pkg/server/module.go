@@ -0,0 +1,22 @@
+package server
+
+import (
+	"context"
+
+	"veidemann-scopeservice/api/scopeservice/v1"
+	"veidemann-scopeservice/pkg/script"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ScopeModuleService struct {
+	scopeservice.UnimplementedScopeModuleServiceServer
+}
+
+func (s *ScopeModuleService) RegisterModule(ctx context.Context, request *scopeservice.RegisterModuleRequest) (*scopeservice.RegisterModuleResponse, error) {
+	if err := script.RegisterModule(request.Name, request.Source); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not register module: %v", err)
+	}
+	return &scopeservice.RegisterModuleResponse{}, nil
+}
@@ -4,30 +4,70 @@ import (
 	"context"
 	"fmt"
 	"github.com/nlnwa/veidemann-api/go/commons/v1"
+	"github.com/nlnwa/veidemann-api/go/frontier/v1"
 	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
 	"github.com/nlnwa/veidemann-api/go/uricanonicalizer/v1"
+	"github.com/nlnwa/whatwg-url/url"
 	otgrpc "github.com/opentracing-contrib/go-grpc"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	"net"
 	"strconv"
+	"time"
+	"veidemann-scopeservice/api/scopeservice/v1"
 	"veidemann-scopeservice/pkg/script"
 	"veidemann-scopeservice/pkg/telemetry"
 )
 
 type GrpcServer struct {
-	listenHost string
-	listenPort int
-	grpcServer *grpc.Server
+	listenHost   string
+	listenPort   int
+	batchWorkers int
+	reflection   bool
+	grpcServer   *grpc.Server
+	health       *health.Server
+	done         chan struct{}
 }
 
-func New(host string, port int) *GrpcServer {
+// readinessInterval is how often the readiness of the services is updated in the health service.
+var readinessInterval = time.Second
+
+// shutdownTimeout is how long Shutdown waits for pending requests to finish.
+var shutdownTimeout = 10 * time.Second
+
+// readinessServices are the services which can not serve requests before the script registry and the scope lists
+// are loaded.
+var readinessServices = []string{
+	scopechecker.ScopesCheckerService_ServiceDesc.ServiceName,
+	scopeservice.ScopeCheckerBatchService_ServiceDesc.ServiceName,
+	scopeservice.ScopeExplainService_ServiceDesc.ServiceName,
+}
+
+// New returns a server listening on host and port. If reflection is true, the gRPC reflection service is registered.
+// The options are added to the gRPC server, e.g. credentials from TLSCredentials.
+func New(host string, port int, batchWorkers int, reflection bool, opts ...grpc.ServerOption) *GrpcServer {
 	s := &GrpcServer{
-		listenHost: host,
-		listenPort: port,
+		listenHost:   host,
+		listenPort:   port,
+		batchWorkers: batchWorkers,
+		reflection:   reflection,
+		health:       health.NewServer(),
+		done:         make(chan struct{}),
 	}
+	tracer := opentracing.GlobalTracer()
+	opts = append([]grpc.ServerOption{
+		grpc.UnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer)),
+		grpc.StreamInterceptor(otgrpc.OpenTracingStreamServerInterceptor(tracer)),
+	}, opts...)
+	s.grpcServer = grpc.NewServer(opts...)
 	return s
 }
 
@@ -36,56 +76,140 @@ func (s *GrpcServer) Start() error {
 	if err != nil {
 		log.Fatal().Msgf("failed to listen: %v", err)
 	}
+	return s.Serve(lis)
+}
 
-	tracer := opentracing.GlobalTracer()
-	var opts = []grpc.ServerOption{
-		grpc.UnaryInterceptor(otgrpc.OpenTracingServerInterceptor(tracer)),
-		grpc.StreamInterceptor(otgrpc.OpenTracingStreamServerInterceptor(tracer)),
-	}
-	s.grpcServer = grpc.NewServer(opts...)
+// Serve registers the services and serves requests on lis until Shutdown is called.
+func (s *GrpcServer) Serve(lis net.Listener) error {
 	scopechecker.RegisterScopesCheckerServiceServer(s.grpcServer, &ScopeCheckerService{})
 	uricanonicalizer.RegisterUriCanonicalizerServiceServer(s.grpcServer, &UriCanonicalizerService{})
+	scopeservice.RegisterUriCanonicalizerBatchServiceServer(s.grpcServer, &UriCanonicalizerBatchService{})
+	scopeservice.RegisterScopeCheckerBatchServiceServer(s.grpcServer, NewScopeCheckerBatchService(s.batchWorkers))
+	scopeservice.RegisterScopeModuleServiceServer(s.grpcServer, &ScopeModuleService{})
+	scopeservice.RegisterScopeExplainServiceServer(s.grpcServer, &ScopeExplainService{})
+	scopeservice.RegisterScopeValidateServiceServer(s.grpcServer, &ScopeValidateService{})
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+	if s.reflection {
+		reflection.Register(s.grpcServer)
+	}
+
+	for name := range s.grpcServer.GetServiceInfo() {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+	s.updateReadiness()
+	go s.watchReadiness()
 
 	log.Info().Msgf("Scope Service listening on %s", lis.Addr())
 	return s.grpcServer.Serve(lis)
 }
 
+// updateReadiness sets the status of the services which depend on the script registry and the scope lists. The
+// overall status of the server, the empty service name, is the readiness.
+func (s *GrpcServer) updateReadiness() {
+	servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+	if script.Ready() {
+		servingStatus = healthpb.HealthCheckResponse_SERVING
+	}
+	s.health.SetServingStatus("", servingStatus)
+	for _, name := range readinessServices {
+		s.health.SetServingStatus(name, servingStatus)
+	}
+}
+
+func (s *GrpcServer) watchReadiness() {
+	ticker := time.NewTicker(readinessInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.updateReadiness()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Shutdown reports all services as NOT_SERVING to the health service, then waits for pending requests to finish
+// and stops the server. Requests still running after shutdownTimeout, e.g. health watches, are canceled.
 func (s *GrpcServer) Shutdown() {
 	log.Info().Msg("Shutting down Scope Service")
-	s.grpcServer.GracefulStop()
+	close(s.done)
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		log.Warn().Msg("Pending requests did not finish, stopping Scope Service")
+		s.grpcServer.Stop()
+	}
 }
 
 type ScopeCheckerService struct {
 	scopechecker.UnimplementedScopesCheckerServiceServer
 }
 
-func (s *ScopeCheckerService) ScopeCheck(_ context.Context, request *scopechecker.ScopeCheckRequest) (*scopechecker.ScopeCheckResponse, error) {
-	telemetry.ScopechecksTotal.Inc()
-	result := script.RunScopeScript(request.ScopeScriptName, request.ScopeScript, request.QueuedUri, request.Debug)
-	telemetry.ScopecheckResponseTotal.With(prometheus.Labels{"code": strconv.Itoa(int(result.ExcludeReason))}).Inc()
+func (s *ScopeCheckerService) ScopeCheck(ctx context.Context, request *scopechecker.ScopeCheckRequest) (*scopechecker.ScopeCheckResponse, error) {
+	compiled := script.CompileScopeScript(request.ScopeScriptName, request.ScopeScript)
+	result, err := runScopeCheck(ctx, compiled, request.QueuedUri, request.Debug)
+	if err != nil {
+		return nil, contextError(err)
+	}
+	return result, nil
+}
+
+// runScopeCheck evaluates one URI and counts it in the scope check metrics. An error is returned if ctx is done
+// before the evaluation completes.
+func runScopeCheck(ctx context.Context, compiled *script.Script, qUri *frontier.QueuedUri, debug bool) (*scopechecker.ScopeCheckResponse, error) {
+	scriptLabel := telemetry.ScriptLabel(compiled.Name())
+	telemetry.ScopechecksTotal.With(prometheus.Labels{"script": scriptLabel}).Inc()
+	result, err := compiled.RunContext(ctx, qUri, debug)
+	if err != nil {
+		return nil, err
+	}
+	statusName := compiled.StatusName(result.ExcludeReason)
+	if statusName == "" {
+		statusName = "unknown"
+	}
+	telemetry.ScopecheckResponseTotal.With(prometheus.Labels{
+		"code":   strconv.Itoa(int(result.ExcludeReason)),
+		"status": statusName,
+		"script": scriptLabel,
+	}).Inc()
 	return result, nil
 }
 
+// contextError converts an error from a done context to a DeadlineExceeded or Canceled status.
+func contextError(err error) error {
+	return status.FromContextError(err).Err()
+}
+
 type UriCanonicalizerService struct {
 	uricanonicalizer.UnimplementedUriCanonicalizerServiceServer
 }
 
-func (u *UriCanonicalizerService) Canonicalize(_ context.Context, request *uricanonicalizer.CanonicalizeRequest) (*uricanonicalizer.CanonicalizeResponse, error) {
+// CanonicalizationProfileKey is the gRPC metadata key for selecting a named canonicalization profile in
+// Canonicalize and CanonicalizeStream. The crawl profile is used if no profile is given. Clients set it with
+// metadata.AppendToOutgoingContext(ctx, CanonicalizationProfileKey, name).
+const CanonicalizationProfileKey = "canonicalization-profile"
+
+func (u *UriCanonicalizerService) Canonicalize(ctx context.Context, request *uricanonicalizer.CanonicalizeRequest) (*uricanonicalizer.CanonicalizeResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, contextError(err)
+	}
 	telemetry.CanonicalizationsTotal.Inc()
-	canonicalized, err := script.CrawlCanonicalizationProfile.Parse(request.Uri)
+	profile, err := canonicalizationProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	canonicalized, err := profile.Parse(request.Uri)
 	if err == nil {
 		return &uricanonicalizer.CanonicalizeResponse{
-			Uri: &commons.ParsedUri{
-				Href:     canonicalized.String(),
-				Scheme:   canonicalized.Scheme(),
-				Host:     canonicalized.Hostname(),
-				Port:     int32(canonicalized.DecodedPort()),
-				Username: canonicalized.Username(),
-				Password: canonicalized.Password(),
-				Path:     canonicalized.Pathname(),
-				Query:    canonicalized.Query(),
-				Fragment: canonicalized.Fragment(),
-			},
+			Uri: asParsedUri(canonicalized),
 		}, nil
 	}
 	return &uricanonicalizer.CanonicalizeResponse{
@@ -93,3 +217,32 @@ func (u *UriCanonicalizerService) Canonicalize(_ context.Context, request *urica
 			Href: request.Uri},
 	}, err
 }
+
+// canonicalizationProfile returns the profile selected with CanonicalizationProfileKey in the metadata of the call,
+// or the crawl profile if no profile is selected.
+func canonicalizationProfile(ctx context.Context) (url.Parser, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if names := md.Get(CanonicalizationProfileKey); len(names) > 0 {
+			profile, err := script.CanonicalizationProfile(names[0])
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			return profile, nil
+		}
+	}
+	return script.CrawlCanonicalizationProfile, nil
+}
+
+func asParsedUri(u *url.Url) *commons.ParsedUri {
+	return &commons.ParsedUri{
+		Href:     u.String(),
+		Scheme:   u.Scheme(),
+		Host:     u.Hostname(),
+		Port:     int32(u.DecodedPort()),
+		Username: u.Username(),
+		Password: u.Password(),
+		Path:     u.Pathname(),
+		Query:    u.Query(),
+		Fragment: u.Fragment(),
+	}
+}
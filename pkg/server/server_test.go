@@ -2,17 +2,36 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"veidemann-scopeservice/api/scopeservice/v1"
 	"veidemann-scopeservice/pkg/script"
+	"veidemann-scopeservice/pkg/telemetry"
 
 	"github.com/nlnwa/veidemann-api/go/commons/v1"
 	"github.com/nlnwa/veidemann-api/go/config/v1"
 	"github.com/nlnwa/veidemann-api/go/frontier/v1"
 	"github.com/nlnwa/veidemann-api/go/scopechecker/v1"
+	"github.com/nlnwa/veidemann-api/go/uricanonicalizer/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -232,6 +251,434 @@ isUrl(param('scope_excludedUris')).then(Blocked)`
 	}
 }
 
+func TestScopeCheckerBatchService_ScopeCheckBatch(t *testing.T) {
+	server := NewScopeCheckerBatchService(3)
+
+	batchScript := `
+isSameHost().otherwise(Blocked)
+maxHopsFromSeed(2).then(TooManyHops)
+test(True).then(Include)`
+
+	tests := []struct {
+		name   string
+		script string
+		qUris  []*frontier.QueuedUri
+		want   []int32
+	}{
+		{"empty", batchScript, nil, []int32{}},
+		{"ordered", batchScript,
+			[]*frontier.QueuedUri{
+				newQUri("http://foo.bar/aa", "http://foo.bar/", "L"),
+				newQUri("http://foo2.bar/aa", "http://foo.bar/", "L"),
+				newQUri("http://foo.bar/aa", "http://foo.bar/", "LLL"),
+				newQUri("http://%00foo.bar/aa", "http://foo.bar/", "L"),
+				newQUri("http://foo.bar/bb", "http://foo.bar/", "LL"),
+			},
+			[]int32{
+				script.Include.AsInt32(),
+				script.Blocked.AsInt32(),
+				script.TooManyHops.AsInt32(),
+				script.IllegalUri.AsInt32(),
+				script.Include.AsInt32(),
+			}},
+		{"headerStatus", "# status: OutOfScope = -5020\ntest(True).then(OutOfScope)",
+			[]*frontier.QueuedUri{newQUri("http://foo.bar/aa", "http://foo.bar/", "L")},
+			[]int32{-5020}},
+		{"badScript", "test(",
+			[]*frontier.QueuedUri{
+				newQUri("http://foo.bar/aa", "http://foo.bar/", "L"),
+				newQUri("http://foo.bar/bb", "http://foo.bar/", "L"),
+			},
+			[]int32{
+				script.RuntimeException.AsInt32(),
+				script.RuntimeException.AsInt32(),
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &scopeservice.ScopeCheckBatchRequest{
+				QueuedUri:       tt.qUris,
+				ScopeScriptName: "scope_script",
+				ScopeScript:     tt.script,
+			}
+
+			compiled := script.CompileScopeScript("scope_script", tt.script)
+			checksBefore := testutil.ToFloat64(telemetry.ScopechecksTotal.WithLabelValues("scope_script"))
+			responsesBefore := make(map[int32]float64)
+			for _, w := range tt.want {
+				responsesBefore[w] = testutil.ToFloat64(telemetry.ScopecheckResponseTotal.WithLabelValues(
+					strconv.Itoa(int(w)), compiled.StatusName(w), "scope_script"))
+			}
+			got, err := server.ScopeCheckBatch(context.TODO(), request)
+			if err != nil {
+				t.Errorf("ScopeCheckBatch() error = %v", err)
+				return
+			}
+			if checks := testutil.ToFloat64(telemetry.ScopechecksTotal.WithLabelValues("scope_script")) - checksBefore; int(checks) != len(tt.qUris) {
+				t.Errorf("ScopeCheckBatch() counted %v scope checks, want %v", checks, len(tt.qUris))
+			}
+			wantResponses := make(map[int32]float64)
+			for _, w := range tt.want {
+				wantResponses[w]++
+			}
+			for w, want := range wantResponses {
+				got := testutil.ToFloat64(telemetry.ScopecheckResponseTotal.WithLabelValues(
+					strconv.Itoa(int(w)), compiled.StatusName(w), "scope_script")) - responsesBefore[w]
+				if got != want {
+					t.Errorf("ScopeCheckBatch() counted %v %v responses, want %v", got, compiled.StatusName(w), want)
+				}
+			}
+			if len(got.Response) != len(tt.want) {
+				t.Fatalf("ScopeCheckBatch() got %v responses, want %v", len(got.Response), len(tt.want))
+			}
+			for i, r := range got.Response {
+				if r.ExcludeReason != tt.want[i] {
+					t.Errorf("ScopeCheckBatch() response %d excludeReason got = %v, want %v", i, r.ExcludeReason, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestContextErrors(t *testing.T) {
+	script.InitializeExecutionLimits(0, time.Second)
+	defer script.InitializeExecutionLimits(script.DefaultMaxExecutionSteps, script.DefaultExecutionTimeout)
+
+	loop := `
+def loop():
+    for i in range(1000000000):
+        pass
+loop()`
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	qUri := newQUri("http://foo.bar/aa", "http://foo.bar/", "L")
+
+	tests := []struct {
+		name     string
+		call     func(ctx context.Context) error
+		timeout  time.Duration
+		wantCode codes.Code
+	}{
+		{"ScopeCheck deadline", func(ctx context.Context) error {
+			_, err := (&ScopeCheckerService{}).ScopeCheck(ctx, &scopechecker.ScopeCheckRequest{QueuedUri: qUri, ScopeScript: loop})
+			return err
+		}, 50 * time.Millisecond, codes.DeadlineExceeded},
+		{"ScopeCheck canceled", func(ctx context.Context) error {
+			_, err := (&ScopeCheckerService{}).ScopeCheck(canceled, &scopechecker.ScopeCheckRequest{QueuedUri: qUri, ScopeScript: loop})
+			return err
+		}, 0, codes.Canceled},
+		{"ScopeCheckBatch deadline", func(ctx context.Context) error {
+			_, err := NewScopeCheckerBatchService(2).ScopeCheckBatch(ctx, &scopeservice.ScopeCheckBatchRequest{
+				QueuedUri:   []*frontier.QueuedUri{qUri, qUri, qUri},
+				ScopeScript: loop,
+			})
+			return err
+		}, 50 * time.Millisecond, codes.DeadlineExceeded},
+		{"Canonicalize canceled", func(ctx context.Context) error {
+			_, err := (&UriCanonicalizerService{}).Canonicalize(canceled, &uricanonicalizer.CanonicalizeRequest{Uri: "http://foo.bar/"})
+			return err
+		}, 0, codes.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			if got := status.Code(tt.call(ctx)); got != tt.wantCode {
+				t.Errorf("got code %v, want %v", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestUriCanonicalizerService_Canonicalize(t *testing.T) {
+	server := &UriCanonicalizerService{}
+
+	tests := []struct {
+		name     string
+		profile  string
+		uri      string
+		want     string
+		wantCode codes.Code
+	}{
+		{"default", "", "http://foo.bar/aa%2520bb?b&a#c", "http://foo.bar/aa%2520bb?a&b", codes.OK},
+		{"scope", "scope", "http://foo.bar/aa%2520bb?b&a#c", "http://foo.bar/aa%20bb?a&b", codes.OK},
+		{"unknown", "missing", "http://foo.bar/", "", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TODO()
+			if tt.profile != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(CanonicalizationProfileKey, tt.profile))
+			}
+			got, err := server.Canonicalize(ctx, &uricanonicalizer.CanonicalizeRequest{Uri: tt.uri})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("Canonicalize() error = %v, want code %v", err, tt.wantCode)
+			}
+			if err == nil && got.Uri.Href != tt.want {
+				t.Errorf("Canonicalize() got = %v, want %v", got.Uri.Href, tt.want)
+			}
+		})
+	}
+}
+
+// canonicalizeStream is a server stream which receives requests and records responses.
+type canonicalizeStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	requests  []*scopeservice.CanonicalizeStreamRequest
+	responses []*scopeservice.CanonicalizeStreamResponse
+}
+
+func (s *canonicalizeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *canonicalizeStream) Recv() (*scopeservice.CanonicalizeStreamRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	r := s.requests[0]
+	s.requests = s.requests[1:]
+	return r, nil
+}
+
+func (s *canonicalizeStream) Send(r *scopeservice.CanonicalizeStreamResponse) error {
+	s.responses = append(s.responses, r)
+	return nil
+}
+
+func TestUriCanonicalizerBatchService_CanonicalizeStream(t *testing.T) {
+	server := &UriCanonicalizerBatchService{}
+
+	stream := &canonicalizeStream{ctx: context.TODO()}
+	for _, uri := range []string{"http://WWW.Øl.no/aa%2520bb?b&a#c", "http://foo.bar:8080/a", "http://[::1]/", "http://exa mple.com/"} {
+		stream.requests = append(stream.requests, &scopeservice.CanonicalizeStreamRequest{Uri: uri})
+	}
+	checks := testutil.ToFloat64(telemetry.CanonicalizationsTotal)
+	if err := server.CanonicalizeStream(stream); err != nil {
+		t.Fatalf("CanonicalizeStream() error = %v", err)
+	}
+	if got := testutil.ToFloat64(telemetry.CanonicalizationsTotal) - checks; got != 4 {
+		t.Errorf("CanonicalizeStream() counted %v canonicalizations, want 4", got)
+	}
+
+	want := []*scopeservice.CanonicalizeStreamResponse{
+		{
+			Uri: "http://WWW.Øl.no/aa%2520bb?b&a#c",
+			CanonicalUri: &commons.ParsedUri{
+				Href: "http://www.xn--l-4ga.no/aa%2520bb?a&b", Scheme: "http", Host: "www.xn--l-4ga.no", Port: 80,
+				Path: "/aa%2520bb", Query: "a&b",
+			},
+			Surt:        "http://(no,xn--l-4ga,www,)/aa%2520bb?a&b",
+			HostAscii:   "www.xn--l-4ga.no",
+			HostUnicode: "www.øl.no",
+		},
+		{
+			Uri: "http://foo.bar:8080/a",
+			CanonicalUri: &commons.ParsedUri{
+				Href: "http://foo.bar:8080/a", Scheme: "http", Host: "foo.bar", Port: 8080, Path: "/a",
+			},
+			Surt:        "http://(bar,foo,:8080)/a",
+			HostAscii:   "foo.bar",
+			HostUnicode: "foo.bar",
+		},
+		{
+			Uri: "http://[::1]/",
+			CanonicalUri: &commons.ParsedUri{
+				Href: "http://[::1]/", Scheme: "http", Host: "[::1]", Port: 80, Path: "/",
+			},
+			Surt:        "http://([::1])/",
+			HostAscii:   "[::1]",
+			HostUnicode: "[::1]",
+		},
+		{
+			Uri:   "http://exa mple.com/",
+			Error: "Error: The host contains a forbidden domain code point: ' '. Url: 'http://exa mple.com/'",
+		},
+	}
+	for i, w := range want {
+		if w.CanonicalUri != nil {
+			hash := sha256.Sum256([]byte(w.CanonicalUri.Href))
+			w.Hash = hex.EncodeToString(hash[:])
+		}
+		if i >= len(stream.responses) {
+			t.Fatalf("CanonicalizeStream() got %d responses, want %d", len(stream.responses), len(want))
+		}
+		if !proto.Equal(stream.responses[i], w) {
+			t.Errorf("CanonicalizeStream() response %d got = %v, want %v", i, stream.responses[i], w)
+		}
+	}
+
+	stream = &canonicalizeStream{ctx: metadata.NewIncomingContext(context.TODO(), metadata.Pairs(CanonicalizationProfileKey, "missing"))}
+	if err := server.CanonicalizeStream(stream); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CanonicalizeStream() with unknown profile error = %v, want code %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestScopeExplainService_Explain(t *testing.T) {
+	server := &ScopeExplainService{}
+	request := &scopechecker.ScopeCheckRequest{
+		QueuedUri:       newQUri("http://foo.bar/aa", "http://foo.bar/", "RL"),
+		ScopeScriptName: "explain",
+		ScopeScript:     "isScheme('ftp').then(Blocked)\nisSameHost().then(Include)",
+	}
+	got, err := server.Explain(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Explain() error = %v", err)
+	}
+	if got.Response.Evaluation != scopechecker.ScopeCheckResponse_INCLUDE {
+		t.Errorf("Explain().Response.Evaluation got = %v, want %v", got.Response.Evaluation, scopechecker.ScopeCheckResponse_INCLUDE)
+	}
+	var functions []string
+	for _, e := range got.Trace {
+		functions = append(functions, fmt.Sprintf("%s:%d:%d %s", e.File, e.Line, e.Col, e.Function))
+	}
+	wantFunctions := []string{"explain:1:9 isScheme", "explain:2:11 isSameHost", "explain:2:18 match.then"}
+	if !reflect.DeepEqual(functions, wantFunctions) {
+		t.Errorf("Explain().Trace got = %v, want %v", functions, wantFunctions)
+	}
+	if got.Decision != 2 {
+		t.Errorf("Explain().Decision got = %v, want 2", got.Decision)
+	}
+	if m := got.Trace[0].Match; m == nil || *m {
+		t.Errorf("Explain().Trace[0].Match got = %v, want false", m)
+	}
+	if s := got.Trace[2].Status; s != "Include" {
+		t.Errorf("Explain().Trace[2].Status got = %v, want Include", s)
+	}
+}
+
+func TestScopeValidateService_ValidateScript(t *testing.T) {
+	server := &ScopeValidateService{}
+
+	tests := []struct {
+		name      string
+		script    string
+		keys      []string
+		wantValid bool
+		want      []*scopeservice.Diagnostic
+	}{
+		{"valid", "isScheme('http').otherwise(Blocked)", nil, true, nil},
+		{"warning", "maxHopsFromSeed(param('hops')).then(TooManyHops)", []string{"scope_hops"}, true,
+			[]*scopeservice.Diagnostic{
+				{Severity: scopeservice.Diagnostic_WARNING, File: "warning", Line: 1, Col: 23, Message: "param 'hops' is not in the annotations"},
+			}},
+		{"error", "isScheme('http').otherwise(Blockd)", nil, false,
+			[]*scopeservice.Diagnostic{
+				{Severity: scopeservice.Diagnostic_ERROR, File: "error", Line: 1, Col: 28, Message: "unknown status Blockd in otherwise()"},
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := server.ValidateScript(context.TODO(), &scopeservice.ValidateScriptRequest{
+				ScopeScriptName: tt.name,
+				ScopeScript:     tt.script,
+				AnnotationKey:   tt.keys,
+			})
+			if err != nil {
+				t.Fatalf("ValidateScript() error = %v", err)
+			}
+			if got.Valid != tt.wantValid {
+				t.Errorf("ValidateScript() valid got = %v, want %v", got.Valid, tt.wantValid)
+			}
+			if len(got.Diagnostic) != len(tt.want) {
+				t.Fatalf("ValidateScript() got %v diagnostics, want %v", got.Diagnostic, tt.want)
+			}
+			for i, d := range got.Diagnostic {
+				if !proto.Equal(d, tt.want[i]) {
+					t.Errorf("ValidateScript() diagnostic %d got = %v, want %v", i, d, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGrpcServer_Health(t *testing.T) {
+	defer func(i, d time.Duration) { readinessInterval, shutdownTimeout = i, d }(readinessInterval, shutdownTimeout)
+	readinessInterval = 10 * time.Millisecond
+	shutdownTimeout = 100 * time.Millisecond
+
+	// A list which fails to load makes the scope checks unready
+	dir := t.TempDir()
+	list := filepath.Join(dir, "blocked.hosts")
+	if err := os.WriteFile(list, []byte("exa mple.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = script.InitializeLists("") }()
+	if err := script.InitializeLists(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := New("", 0, 1, true)
+	go func() { _ = server.Serve(lis) }()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	// eventually waits for the service to have the wanted status
+	eventually := func(service string, want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		var got healthpb.HealthCheckResponse_ServingStatus
+		for i := 0; i < 100; i++ {
+			r, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+			if err != nil {
+				t.Fatalf("Check(%q) error = %v", service, err)
+			}
+			if got = r.Status; got == want {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		t.Errorf("Check(%q) got = %v, want %v", service, got, want)
+	}
+
+	scopeCheckService := scopechecker.ScopesCheckerService_ServiceDesc.ServiceName
+	canonicalizerService := uricanonicalizer.UriCanonicalizerService_ServiceDesc.ServiceName
+	eventually("", healthpb.HealthCheckResponse_NOT_SERVING)
+	eventually(scopeCheckService, healthpb.HealthCheckResponse_NOT_SERVING)
+	eventually(canonicalizerService, healthpb.HealthCheckResponse_SERVING)
+
+	if err := os.WriteFile(list, []byte("example.com\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	eventually("", healthpb.HealthCheckResponse_SERVING)
+	eventually(scopeCheckService, healthpb.HealthCheckResponse_SERVING)
+
+	if _, ok := server.grpcServer.GetServiceInfo()["grpc.reflection.v1.ServerReflection"]; !ok {
+		t.Errorf("reflection service is not registered")
+	}
+
+	watch, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{Service: canonicalizerService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, err := watch.Recv(); err != nil || r.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("Watch() got = %v, %v, want SERVING", r, err)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		server.Shutdown()
+		close(stopped)
+	}()
+	if r, err := watch.Recv(); err != nil || r.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("Watch() after Shutdown() got = %v, %v, want NOT_SERVING", r, err)
+	}
+	<-stopped
+}
+
 func newQUri(uri, seed, discoveryPath string) *frontier.QueuedUri {
 	return &frontier.QueuedUri{
 		Id:                  "id1",
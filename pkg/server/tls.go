@@ -0,0 +1,158 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/credentials"
+)
+
+// Client certificate verification modes for TLSConfig.ClientAuth.
+const (
+	ClientAuthNone    = "none"    // client certificates are not requested
+	ClientAuthRequest = "request" // client certificates are verified if the client sends one
+	ClientAuthRequire = "require" // clients must send a valid certificate
+)
+
+// TLSConfig configures TLS for the gRPC listener.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is a PEM bundle with the CAs which client certificates are verified against.
+	ClientCAFile string
+	// ClientAuth is one of ClientAuthNone, ClientAuthRequest and ClientAuthRequire. The default is
+	// ClientAuthRequire if ClientCAFile is set and ClientAuthNone otherwise.
+	ClientAuth string
+}
+
+// TLSCredentials returns transport credentials for the gRPC server. The certificate, key and client CAs are
+// reloaded when the files change on disk, the new files are used for new connections.
+func TLSCredentials(c TLSConfig) (credentials.TransportCredentials, error) {
+	if c.CertFile == "" || c.KeyFile == "" {
+		return nil, errors.New("both a TLS certificate and a key are required")
+	}
+	clientAuth := tls.NoClientCert
+	switch c.ClientAuth {
+	case "":
+		if c.ClientCAFile != "" {
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+	case ClientAuthNone:
+	case ClientAuthRequest:
+		clientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		clientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth '%s', must be one of %s, %s or %s", c.ClientAuth, ClientAuthNone, ClientAuthRequest, ClientAuthRequire)
+	}
+	if clientAuth != tls.NoClientCert && c.ClientCAFile == "" {
+		return nil, fmt.Errorf("client auth '%s' requires a client CA bundle", c.ClientAuth)
+	}
+
+	r := &certReloader{config: c, clientAuth: clientAuth}
+	if err := r.load(r.readModTimes()); err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(&tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.getConfigForClient,
+	}), nil
+}
+
+// certReloader holds the TLS configuration loaded from disk. The files are checked for changes on each handshake.
+type certReloader struct {
+	config     TLSConfig
+	clientAuth tls.ClientAuthType
+
+	mu       sync.Mutex
+	tls      *tls.Config
+	modTimes []time.Time
+}
+
+func (r *certReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	if modTimes, changed := r.changed(); changed {
+		if err := r.load(modTimes); err != nil {
+			// Try again when the files change
+			r.mu.Lock()
+			r.modTimes = modTimes
+			r.mu.Unlock()
+			log.Error().Err(err).Msg("Failed to reload TLS certificates, keeping previous version")
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.tls, nil
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	return files
+}
+
+// readModTimes returns the modification times of the files. A file which can not be read has the zero time.
+func (r *certReloader) readModTimes() []time.Time {
+	var modTimes []time.Time
+	for _, f := range r.files() {
+		var t time.Time
+		if fi, err := os.Stat(f); err == nil {
+			t = fi.ModTime()
+		}
+		modTimes = append(modTimes, t)
+	}
+	return modTimes
+}
+
+// changed returns the current modification times of the files and whether they changed since they were loaded.
+func (r *certReloader) changed() ([]time.Time, bool) {
+	modTimes := r.readModTimes()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, t := range modTimes {
+		if !t.Equal(r.modTimes[i]) {
+			return modTimes, true
+		}
+	}
+	return modTimes, false
+}
+
+// load reads the files, which had the given modification times before they were read.
+func (r *certReloader) load(modTimes []time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	// The config returned by GetConfigForClient replaces the one from credentials.NewTLS, so ALPN must be set here
+	// for gRPC clients which require HTTP/2 to be negotiated
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   r.clientAuth,
+		NextProtos:   []string{"h2"},
+	}
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to load client CA bundle: %w", err)
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in client CA bundle %s", r.config.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tls = config
+	r.modTimes = modTimes
+	log.Info().Msgf("Loaded TLS certificate %s", r.config.CertFile)
+	return nil
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCA is a certificate authority which issues certificates for tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a PEM encoded certificate and key. A server certificate is valid for 127.0.0.1.
+func (ca *testCA) issue(t *testing.T, name string, server bool) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if server {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		template.IPAddresses = []net.IP{net.ParseIP("127.0.0.1")}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func writeFile(t *testing.T, name string, data []byte, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(name, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTLSCredentials_Config(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "ca")
+	cert, key := ca.issue(t, "server", true)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writeFile(t, certFile, cert, time.Now())
+	writeFile(t, keyFile, key, time.Now())
+	writeFile(t, caFile, ca.pem, time.Now())
+	emptyFile := filepath.Join(dir, "empty.crt")
+	writeFile(t, emptyFile, nil, time.Now())
+
+	tests := []struct {
+		name    string
+		config  TLSConfig
+		wantErr bool
+	}{
+		{"server only", TLSConfig{CertFile: certFile, KeyFile: keyFile}, false},
+		{"mutual", TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}, false},
+		{"request", TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: ClientAuthRequest}, false},
+		{"missing key", TLSConfig{CertFile: certFile}, true},
+		{"missing client ca", TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientAuth: ClientAuthRequire}, true},
+		{"unknown client auth", TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: "always"}, true},
+		{"mismatched key", TLSConfig{CertFile: certFile, KeyFile: caFile}, true},
+		{"empty client ca", TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: emptyFile}, true},
+		{"missing file", TLSConfig{CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := TLSCredentials(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("TLSCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTLSCredentials_Serve(t *testing.T) {
+	dir := t.TempDir()
+	serverCA := newTestCA(t, "server-ca")
+	clientCA := newTestCA(t, "client-ca")
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	cert, key := serverCA.issue(t, "server", true)
+	modTime := time.Now().Add(-time.Minute)
+	writeFile(t, certFile, cert, modTime)
+	writeFile(t, keyFile, key, modTime)
+	writeFile(t, caFile, clientCA.pem, modTime)
+
+	clientCertPEM, clientKeyPEM := clientCA.issue(t, "client", false)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCertPEM, otherKeyPEM := newTestCA(t, "other-ca").issue(t, "other", false)
+	otherCert, err := tls.X509KeyPair(otherCertPEM, otherKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// check calls the health service with a client which trusts ca and sends the certificates
+	check := func(addr string, ca *testCA, certs ...tls.Certificate) error {
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.cert)
+		creds := credentials.NewTLS(&tls.Config{RootCAs: roots, Certificates: certs})
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		return err
+	}
+
+	serve := func(t *testing.T, clientAuth string) string {
+		t.Helper()
+		creds, err := TLSCredentials(TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile, ClientAuth: clientAuth})
+		if err != nil {
+			t.Fatal(err)
+		}
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		server := New("", 0, 1, false, grpc.Creds(creds))
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			_ = server.Serve(lis)
+		}()
+		t.Cleanup(func() {
+			server.Shutdown()
+			<-stopped
+		})
+		return lis.Addr().String()
+	}
+
+	t.Run("require", func(t *testing.T) {
+		addr := serve(t, "")
+		if err := check(addr, serverCA, clientCert); err != nil {
+			t.Errorf("Check() with client certificate error = %v", err)
+		}
+		if err := check(addr, serverCA); err == nil {
+			t.Errorf("Check() without client certificate succeeded")
+		}
+		if err := check(addr, serverCA, otherCert); err == nil {
+			t.Errorf("Check() with untrusted client certificate succeeded")
+		}
+	})
+
+	t.Run("request", func(t *testing.T) {
+		addr := serve(t, ClientAuthRequest)
+		if err := check(addr, serverCA); err != nil {
+			t.Errorf("Check() without client certificate error = %v", err)
+		}
+		if err := check(addr, serverCA, clientCert); err != nil {
+			t.Errorf("Check() with client certificate error = %v", err)
+		}
+	})
+
+	t.Run("alpn", func(t *testing.T) {
+		addr := serve(t, ClientAuthRequest)
+		roots := x509.NewCertPool()
+		roots.AddCert(serverCA.cert)
+		conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: roots, NextProtos: []string{"h2"}})
+		if err != nil {
+			t.Fatalf("Dial() error = %v", err)
+		}
+		defer conn.Close()
+		if got := conn.ConnectionState().NegotiatedProtocol; got != "h2" {
+			t.Errorf("NegotiatedProtocol got = %q, want h2", got)
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		addr := serve(t, "")
+
+		// A broken key keeps the previous certificate
+		writeFile(t, keyFile, []byte("broken"), modTime.Add(time.Second))
+		if err := check(addr, serverCA, clientCert); err != nil {
+			t.Errorf("Check() after failed reload error = %v", err)
+		}
+
+		newCA := newTestCA(t, "new-server-ca")
+		cert, key := newCA.issue(t, "server", true)
+		writeFile(t, certFile, cert, modTime.Add(2*time.Second))
+		writeFile(t, keyFile, key, modTime.Add(2*time.Second))
+		if err := check(addr, newCA, clientCert); err != nil {
+			t.Errorf("Check() with new server certificate error = %v", err)
+		}
+		if err := check(addr, serverCA, clientCert); err == nil {
+			t.Errorf("Check() trusting the old server CA succeeded")
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+
+	"veidemann-scopeservice/api/scopeservice/v1"
+	"veidemann-scopeservice/pkg/script"
+)
+
+type ScopeValidateService struct {
+	scopeservice.UnimplementedScopeValidateServiceServer
+}
+
+func (s *ScopeValidateService) ValidateScript(ctx context.Context, request *scopeservice.ValidateScriptRequest) (*scopeservice.ValidateScriptResponse, error) {
+	var annotations []string
+	if len(request.AnnotationKey) > 0 {
+		annotations = request.AnnotationKey
+	}
+	diagnostics := script.ValidateScript(request.ScopeScriptName, request.ScopeScript, annotations)
+
+	response := &scopeservice.ValidateScriptResponse{Valid: !script.HasErrors(diagnostics)}
+	for _, d := range diagnostics {
+		severity := scopeservice.Diagnostic_ERROR
+		if d.Severity == script.SeverityWarning {
+			severity = scopeservice.Diagnostic_WARNING
+		}
+		response.Diagnostic = append(response.Diagnostic, &scopeservice.Diagnostic{
+			Severity: severity,
+			File:     d.File,
+			Line:     d.Line,
+			Col:      d.Col,
+			Message:  d.Message,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,71 @@
+package telemetry
+
+import "sync"
+
+// DefaultMaxScriptLabels is the number of distinct script names used as metric labels if InitializeScriptLabels
+// is never called.
+const DefaultMaxScriptLabels = 100
+
+const (
+	// OtherScriptLabel is the script label used for scripts which are not allowed or exceed the max number of labels.
+	OtherScriptLabel = "other"
+	// UnnamedScriptLabel is the script label used for scripts without name.
+	UnnamedScriptLabel = "unnamed"
+)
+
+var scriptLabels = newScriptLabelSet(nil, DefaultMaxScriptLabels)
+
+// scriptLabelSet limits the number of distinct values of the script label.
+type scriptLabelSet struct {
+	mu    sync.Mutex
+	allow map[string]bool
+	max   int
+	seen  map[string]bool
+}
+
+func newScriptLabelSet(allow []string, max int) *scriptLabelSet {
+	s := &scriptLabelSet{max: max, seen: make(map[string]bool)}
+	if len(allow) > 0 {
+		s.allow = make(map[string]bool, len(allow))
+		for _, a := range allow {
+			s.allow[a] = true
+		}
+	}
+	return s
+}
+
+// InitializeScriptLabels sets which script names are used as metric labels. If allow is not empty, only the names
+// in allow are used. Otherwise the first max distinct names are used. A max less than one means no limit.
+// Other scripts are labeled OtherScriptLabel. It must be called before any metrics are recorded, since metrics already
+// recorded keep the labels they were recorded with.
+func InitializeScriptLabels(allow []string, max int) {
+	scriptLabels = newScriptLabelSet(allow, max)
+}
+
+// ScriptLabel returns the value of the script label for the named script.
+func ScriptLabel(name string) string {
+	if name == "" {
+		return UnnamedScriptLabel
+	}
+	return scriptLabels.label(name)
+}
+
+func (s *scriptLabelSet) label(name string) string {
+	if s.allow != nil {
+		if s.allow[name] {
+			return name
+		}
+		return OtherScriptLabel
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[name] {
+		return name
+	}
+	if s.max > 0 && len(s.seen) >= s.max {
+		return OtherScriptLabel
+	}
+	s.seen[name] = true
+	return name
+}
@@ -0,0 +1,35 @@
+package telemetry
+
+import "testing"
+
+func TestScriptLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []string
+		max   int
+		names []string
+		want  []string
+	}{
+		{"unnamed", nil, 2, []string{""}, []string{UnnamedScriptLabel}},
+		{"max", nil, 2,
+			[]string{"a", "b", "c", "a", "b", "c"},
+			[]string{"a", "b", OtherScriptLabel, "a", "b", OtherScriptLabel}},
+		{"no limit", nil, 0,
+			[]string{"a", "b", "c"},
+			[]string{"a", "b", "c"}},
+		{"allowlist", []string{"b", "c"}, 1,
+			[]string{"a", "b", "c", ""},
+			[]string{OtherScriptLabel, "b", "c", UnnamedScriptLabel}},
+	}
+	defer InitializeScriptLabels(nil, DefaultMaxScriptLabels)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			InitializeScriptLabels(tt.allow, tt.max)
+			for i, n := range tt.names {
+				if got := ScriptLabel(n); got != tt.want[i] {
+					t.Errorf("ScriptLabel(%q) = %v, want %v", n, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
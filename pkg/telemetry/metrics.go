@@ -28,37 +28,128 @@ var (
 		Help:      "Total URIs canonicalized",
 	})
 
-	ScopechecksTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	ScopechecksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: metricsNs,
 		Subsystem: metricsSubsystem,
 		Name:      "scopechecks_total",
-		Help:      "Total URIs checked for scope inclusion",
-	})
+		Help:      "Total URIs checked for scope inclusion for each script",
+	},
+		[]string{"script"},
+	)
 
 	ScopecheckResponseTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: metricsNs,
 		Subsystem: metricsSubsystem,
 		Name:      "scopecheck_response_total",
-		Help:      "Total scopecheck responses for each response code",
+		Help:      "Total scopecheck responses for each response code and script",
 	},
-		[]string{"code"},
+		[]string{"code", "status", "script"},
 	)
 
-	CompileScriptSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+	CompileScriptSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: metricsNs,
 		Subsystem: metricsSubsystem,
 		Name:      "script_compile_seconds",
 		Help:      "Time for compiling a script in seconds",
 		Buckets:   []float64{.005, .01, .025, .05, .075, .1, .25, .5, .75, 1, 2.5, 5, 7.5, 10, 20, 30, 40, 50, 60, 120, 180, 240},
+	},
+		[]string{"script"},
+	)
+
+	ScriptCacheHitsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "script_cache_hits_total",
+		Help:      "Total compiled script cache hits",
 	})
 
-	ExecuteScriptSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+	ScriptCacheMissesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "script_cache_misses_total",
+		Help:      "Total compiled script cache misses",
+	})
+
+	ScriptCacheEvictionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "script_cache_evictions_total",
+		Help:      "Total compiled scripts evicted from cache",
+	})
+
+	RegisteredScriptInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "registered_script_info",
+		Help:      "Version hash of each script loaded from the script registry",
+	},
+		[]string{"script", "hash"},
+	)
+
+	RegisteredScriptReloadErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "registered_script_reload_errors_total",
+		Help:      "Total failed reloads of scripts in the script registry",
+	},
+		[]string{"script"},
+	)
+
+	ExecuteScriptSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: metricsNs,
 		Subsystem: metricsSubsystem,
 		Name:      "script_execute_seconds",
 		Help:      "Time for executing a script in seconds",
 		Buckets:   []float64{.005, .01, .025, .05, .075, .1, .25, .5, .75, 1, 2.5, 5, 7.5, 10, 20, 30, 40, 50, 60, 120, 180, 240},
-	})
+	},
+		[]string{"script"},
+	)
+
+	AbandonedEvaluationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "abandoned_evaluations_total",
+		Help:      "Total script evaluations abandoned because the request was canceled or its deadline exceeded",
+	},
+		[]string{"reason"},
+	)
+
+	ScopeListEntries = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "scope_list_entries",
+		Help:      "Number of entries in each scope list",
+	},
+		[]string{"list"},
+	)
+
+	ScopeListLastReloadSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "scope_list_last_reload_timestamp_seconds",
+		Help:      "Time of the last successful reload of each scope list as seconds since the epoch",
+	},
+		[]string{"list"},
+	)
+
+	ScopeListLoadSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "scope_list_load_seconds",
+		Help:      "Time for loading a scope list in seconds",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	},
+		[]string{"list"},
+	)
+
+	ScopeListReloadErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNs,
+		Subsystem: metricsSubsystem,
+		Name:      "scope_list_reload_errors_total",
+		Help:      "Total failed reloads of scope lists",
+	},
+		[]string{"list"},
+	)
 )
 
 const (
@@ -52,6 +52,16 @@ func NewMetricsServer(listenInterface string, listenPort int, path string) *Metr
 			ScopecheckResponseTotal,
 			CompileScriptSeconds,
 			ExecuteScriptSeconds,
+			AbandonedEvaluationsTotal,
+			ScriptCacheHitsTotal,
+			ScriptCacheMissesTotal,
+			ScriptCacheEvictionsTotal,
+			RegisteredScriptInfo,
+			RegisteredScriptReloadErrorsTotal,
+			ScopeListEntries,
+			ScopeListLastReloadSeconds,
+			ScopeListLoadSeconds,
+			ScopeListReloadErrorsTotal,
 			collectors.NewBuildInfoCollector(),
 		)
 	})